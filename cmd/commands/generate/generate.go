@@ -26,6 +26,8 @@ import (
 	"github.com/beego/bee/utils"
 )
 
+var docsCheck bool
+
 var CmdGenerate = &commands.Command{
 	UsageLine: "generate [command]",
 	Short:     "Source code generator",
@@ -51,7 +53,7 @@ var CmdGenerate = &commands.Command{
 
   ▶ {{"To generate swagger doc file:"|bold}}
 
-     $ bee generate docs
+     $ bee generate docs [-check] [-strict] [-bundle] [-spec=2.0] [-apiversion=1.0.0]
 
   ▶ {{"To generate a test case:"|bold}}
 
@@ -72,6 +74,11 @@ func init() {
 	CmdGenerate.Flag.Var(&generate.Level, "level", "Either 1, 2 or 3. i.e. 1=models; 2=models and controllers; 3=models, controllers and routers.")
 	CmdGenerate.Flag.Var(&generate.Fields, "fields", "List of table Fields.")
 	CmdGenerate.Flag.Var(&generate.DDL, "ddl", "Generate DDL Migration")
+	CmdGenerate.Flag.Var(&swaggergen.APIVersion, "apiversion", "Version of the API documented by the swagger docs, overriding @APIVersion.")
+	CmdGenerate.Flag.BoolVar(&docsCheck, "check", false, "Check whether the swagger docs are up to date instead of generating them.")
+	CmdGenerate.Flag.BoolVar(&swaggergen.Bundle, "bundle", false, "Inline the swagger definitions where they are used, for the tools which cannot resolve $ref.")
+	CmdGenerate.Flag.StringVar(&swaggergen.Spec, "spec", "2.0", "Version of the specification of the swagger docs, either 2.0 for Swagger 2.0 or 3.0 for OpenAPI 3.0.")
+	CmdGenerate.Flag.BoolVar(&swaggergen.Strict, "strict", false, "Fail when a swagger definition has no properties, unless its type is documented with @EmptyModel.")
 	commands.AvailableCommands = append(commands.AvailableCommands, CmdGenerate)
 }
 
@@ -95,7 +102,13 @@ func GenerateCode(cmd *commands.Command, args []string) int {
 	case "scaffold":
 		scaffold(cmd, args, currpath)
 	case "docs":
-		swaggergen.GenerateDocs(currpath)
+		cmd.Flag.Parse(args[1:])
+		if docsCheck {
+			return checkDocs(currpath)
+		}
+		if err := swaggergen.GenerateDocs(currpath); err != nil {
+			beeLogger.Log.Fatalf("Error while generating the docs: %s", err)
+		}
 	case "appcode":
 		appCode(cmd, args, currpath)
 	case "migration":
@@ -113,6 +126,19 @@ func GenerateCode(cmd *commands.Command, args []string) int {
 	return 0
 }
 
+func checkDocs(currpath string) int {
+	upToDate, err := swaggergen.CheckDocs(currpath)
+	if err != nil {
+		beeLogger.Log.Fatalf("Error while generating the docs: %s", err)
+	}
+	if !upToDate {
+		beeLogger.Log.Error("Docs are out of date. Run: bee generate docs")
+		return 1
+	}
+	beeLogger.Log.Success("Docs are up to date!")
+	return 0
+}
+
 func scaffold(cmd *commands.Command, args []string, currpath string) {
 	if len(args) < 2 {
 		beeLogger.Log.Fatal("Wrong number of arguments. Run: bee help generate")
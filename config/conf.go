@@ -38,6 +38,7 @@ var Conf = struct {
 	EnableReload       bool              `json:"enable_reload" yaml:"enable_reload"`
 	EnableNotification bool              `json:"enable_notification" yaml:"enable_notification"`
 	Scripts            map[string]string `json:"scripts" yaml:"scripts"`
+	Swagger            swagger           `json:"swagger" yaml:"swagger"`
 }{
 	WatchExts:       []string{".go"},
 	WatchExtsStatic: []string{".html", ".tpl", ".js", ".css"},
@@ -56,6 +57,10 @@ var Conf = struct {
 	},
 	EnableNotification: true,
 	Scripts:            map[string]string{},
+	Swagger: swagger{
+		IgnoreTag:      "ignore",
+		MaxDefinitions: 1000,
+	},
 }
 
 // dirStruct describes the application's directory structure
@@ -80,6 +85,23 @@ type database struct {
 	Dir    string
 }
 
+// swagger holds the options used when generating the swagger docs
+type swagger struct {
+	IgnoreTag         string         `json:"ignore_tag" yaml:"ignore_tag"`                 // Struct tag which excludes a field from the models.
+	MaxEnumValues     int            `json:"max_enum_values" yaml:"max_enum_values"`       // Maximum number of documented enum values, 0 for no limit.
+	PathSecurity      []pathSecurity `json:"path_security" yaml:"path_security"`           // Security of the paths protected by filters rather than annotations.
+	OmitEmptyOptional bool           `json:"omitempty_optional" yaml:"omitempty_optional"` // Fields are required unless their json tag has omitempty, whatever their required tag.
+	DefaultTag        string         `json:"default_tag" yaml:"default_tag"`               // Tag of the operations of the root namespace, which have none otherwise.
+	MaxDefinitions    int            `json:"max_definitions" yaml:"max_definitions"`       // Number of definitions above which the packages contributing the most are listed, 0 for no limit.
+}
+
+// pathSecurity holds the security requirements of the paths matching a glob, e.g. /v1/admin/*
+// where a trailing * matches any number of path elements
+type pathSecurity struct {
+	Path     string   `json:"path" yaml:"path"`
+	Security []string `json:"security" yaml:"security"` // Requirements written as the ones of @Security, e.g. "oauth read write"
+}
+
 // LoadConfig loads the bee tool configuration.
 // It looks for Beefile or bee.json in the current path,
 // and falls back to default configuration in case not found.
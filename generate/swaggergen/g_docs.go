@@ -19,8 +19,13 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -30,11 +35,13 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"gopkg.in/yaml.v2"
 
 	"github.com/astaxie/beego/utils"
+	"github.com/beego/bee/config"
 	"github.com/beego/bee/generate/swaggergen/swagger"
 	"github.com/beego/bee/logger"
 	bu "github.com/beego/bee/utils"
@@ -46,6 +53,12 @@ const (
 	aplain = "text/plain"
 	ahtml  = "text/html"
 	aform  = "multipart/form-data"
+	aurl   = "application/x-www-form-urlencoded"
+	aoctet = "application/octet-stream"
+	amixed = "multipart/mixed"
+	// JSON Patch, RFC 6902, and JSON Merge Patch, RFC 7396, bodies of PATCH requests
+	ajsonPatch  = "application/json-patch+json"
+	amergePatch = "application/merge-patch+json"
 )
 
 const (
@@ -54,16 +67,68 @@ const (
 	astTypeMap    = "map"
 )
 
+// swaggerInfoFile holds the info block shared by the router annotations of several specs
+const swaggerInfoFile = "swaggerinfo.yml"
+
+// swaggerTypesFile maps the types of other packages to the swagger types documenting them,
+// e.g. decimal.Decimal: number:double
+const swaggerTypesFile = "swagger.types.yml"
+
+// APIVersion overrides the @APIVersion of the router comments when set
+var APIVersion bu.DocValue
+
+// Bundle makes the docs self-contained, the definitions being inlined where they are used
+var Bundle bool
+
+// Spec is the version of the specification the docs follow, either 2.0 for Swagger 2.0 or 3.0
+// for OpenAPI 3.0, which is converted from the Swagger 2.0 document
+var Spec = "2.0"
+
+// Strict makes the generation fail on definitions without any property, which usually
+// are types that could not be resolved. Types documented with @EmptyModel are allowed.
+var Strict bool
+
+// AnnotationHandler documents a custom annotation of a controller method on its operation,
+// value being the text which follows the annotation.
+type AnnotationHandler func(op *swagger.Operation, value string)
+
+var annotationHandlers = make(map[string]AnnotationHandler) //annotation:handler
+
+// RegisterAnnotation registers the handler of a custom annotation such as @Audit, which is
+// invoked for the comments of controller methods the generator does not know about.
+func RegisterAnnotation(annotation string, handler AnnotationHandler) {
+	if !strings.HasPrefix(annotation, "@") {
+		annotation = "@" + annotation
+	}
+	annotationHandlers[annotation] = handler
+}
+
 var pkgCache map[string]struct{} //pkg:controller:function:comments comments: key:value
 var controllerComments map[string]string
+var controllerTags map[string][]string           //controllername:additional tags
+var controllerDefaultResponses map[string]string //controllername:default response model
+var deprecatedControllers map[string]bool        //controllername:all of its operations are deprecated
 var importlist map[string]string
 var controllerList map[string]map[string]*swagger.Item //controllername Paths items
 var modelsList map[string]map[string]swagger.Schema
 var rootapi swagger.Swagger
 var astPkgs []*ast.Package
+var workspaceModules map[string]string          //module path:module root directory
+var astCache map[string]map[string]*ast.Package //real path:package name:package
+var responseHeaders map[string]swagger.Header   //header name:header sent with every response
+var emptyModels map[string]bool                 //definition name:documented as intentionally empty
+var operationIDs map[string]string              //operationId:controller method documented by it
+var tagConsumes map[string][]string             //tag name:media types consumed by its operations by default
+var tagProduces map[string][]string             //tag name:media types produced by its operations by default
+var nonStringMapKeys map[string]bool            //package.struct.field:key type:warned about its non-string map keys
+var namespacePrefix string                      //prefix of the namespace being traversed
+var baseNamespace *ast.CallExpr                 //namespace of the base path, when nested in the one being traversed
+var controllerInclusions map[string]int         //controllername:number of namespaces including it so far
+var basicTypes map[string]string                //type name:swagger type and format, the builtin ones and those of swagger.types.yml
+var stdlibObject map[string]string              //type expression:type name of basicTypes, e.g. &{time Time}:time.Time
 
 // refer to builtin.go
-var basicTypes = map[string]string{
+var builtinBasicTypes = map[string]string{
 	"bool":       "boolean:",
 	"uint":       "integer:int32",
 	"uint8":      "integer:int32",
@@ -86,11 +151,21 @@ var basicTypes = map[string]string{
 	// builtin golang objects
 	"time.Time":       "string:datetime",
 	"json.RawMessage": "object:",
+	// numeric types of the standard library, a duration being a number of nanoseconds
+	"time.Duration": "integer:int64",
+	"time.Month":    "integer:int32",
+	"time.Weekday":  "integer:int32",
+	// a well-known third-party type, which is marshalled as a string
+	"uuid.UUID": "string:uuid",
 }
 
-var stdlibObject = map[string]string{
+var builtinObjects = map[string]string{
 	"&{time Time}":       "time.Time",
 	"&{json RawMessage}": "json.RawMessage",
+	"&{time Duration}":   "time.Duration",
+	"&{time Month}":      "time.Month",
+	"&{time Weekday}":    "time.Weekday",
+	"&{uuid UUID}":       "uuid.UUID",
 }
 
 var httpMethods = map[string]bool{
@@ -104,12 +179,41 @@ var httpMethods = map[string]bool{
 }
 
 func init() {
+	resetDocs()
+
+	// @SecurityDefinition must not be taken for @Security, nor @LicenseUrl for @License
+	sort.SliceStable(rootAnnotations, func(i, j int) bool {
+		return len(rootAnnotations[i].prefix) > len(rootAnnotations[j].prefix)
+	})
+	sort.SliceStable(operationAnnotations, func(i, j int) bool {
+		return len(operationAnnotations[i].prefix) > len(operationAnnotations[j].prefix)
+	})
+}
+
+// resetDocs clears the state gathered while building the docs
+func resetDocs() {
+	resetTypes()
+	rootapi = swagger.Swagger{}
+	namespacePrefix = ""
+	baseNamespace = nil
 	pkgCache = make(map[string]struct{})
 	controllerComments = make(map[string]string)
+	controllerTags = make(map[string][]string)
+	controllerDefaultResponses = make(map[string]string)
+	deprecatedControllers = make(map[string]bool)
 	importlist = make(map[string]string)
 	controllerList = make(map[string]map[string]*swagger.Item)
 	modelsList = make(map[string]map[string]swagger.Schema)
 	astPkgs = make([]*ast.Package, 0)
+	workspaceModules = make(map[string]string)
+	astCache = make(map[string]map[string]*ast.Package)
+	emptyModels = make(map[string]bool)
+	operationIDs = make(map[string]string)
+	tagConsumes = make(map[string][]string)
+	tagProduces = make(map[string][]string)
+	nonStringMapKeys = make(map[string]bool)
+	responseHeaders = make(map[string]swagger.Header)
+	controllerInclusions = make(map[string]int)
 }
 
 // ParsePackagesFromDir parses packages from a given directory
@@ -149,126 +253,449 @@ func ParsePackagesFromDir(dirpath string) {
 }
 
 func parsePackageFromDir(astPkgs *[]*ast.Package, path string) error {
+	folderPkgs, err := parseDir(path)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range folderPkgs {
+		*astPkgs = append(*astPkgs, v)
+	}
+
+	return nil
+}
+
+// parseDir parses the go files of a directory, each directory being parsed
+// at most once per run as the packages are cached by their real path.
+func parseDir(path string) (map[string]*ast.Package, error) {
+	realPath := canonicalPath(path)
+	if realPath == "" {
+		realPath = path
+	}
+	if pkgs, ok := astCache[realPath]; ok {
+		return pkgs, nil
+	}
+
 	fileSet := token.NewFileSet()
-	folderPkgs, err := parser.ParseDir(fileSet, path, func(info os.FileInfo) bool {
+	pkgs, err := parser.ParseDir(fileSet, realPath, func(info os.FileInfo) bool {
 		name := info.Name()
 		return !info.IsDir() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".go")
 	}, parser.ParseComments)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	astCache[realPath] = pkgs
+	return pkgs, nil
+}
 
-	for _, v := range folderPkgs {
-		*astPkgs = append(*astPkgs, v)
+// canonicalPath resolves the symbolic links of path and makes it absolute, so that a directory
+// is known by one path whichever way it was reached, or returns "" when path does not exist
+func canonicalPath(path string) string {
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ""
+	}
+	if absPath, err := filepath.Abs(realPath); err == nil {
+		return absPath
+	}
+	return realPath
+}
+
+// fileDir returns the directory of a parsed source file
+func fileDir(fl *ast.File) string {
+	for dir, pkgs := range astCache {
+		for _, pkg := range pkgs {
+			for _, f := range pkg.Files {
+				if f == fl {
+					return dir
+				}
+			}
+		}
 	}
+	return ""
+}
+
+// rootAnnotation parses the annotation of router.go starting with prefix
+type rootAnnotation struct {
+	prefix string
+	parse  func(value string) error
+}
+
+// rootAnnotations lists the annotations of router.go, init sorts them so that the longest
+// prefixes are matched first
+var rootAnnotations = []rootAnnotation{
+	{"@APIVersion", func(value string) error { rootapi.Infos.Version = value; return nil }},
+	{"@Title", func(value string) error { rootapi.Infos.Title = value; return nil }},
+	{"@Description", func(value string) error { rootapi.Infos.Description = value; return nil }},
+	{"@TermsOfServiceUrl", func(value string) error {
+		if u, err := url.Parse(value); err != nil || !u.IsAbs() || u.Host == "" {
+			beeLogger.Log.Warnf("@TermsOfServiceUrl is not a valid URL: %s", value)
+		}
+		rootapi.Infos.TermsOfService = value
+		return nil
+	}},
+	{"@InfoExtension", func(value string) error {
+		name, pos := peekNextSplitString(value)
+		if !strings.HasPrefix(name, "x-") {
+			beeLogger.Log.Warnf("The name of @InfoExtension %s should start with x-", name)
+			return nil
+		}
+		var ext interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(value[pos:])), &ext); err != nil {
+			ext = strings.Trim(strings.TrimSpace(value[pos:]), `"`)
+		}
+		if rootapi.Infos.Extensions == nil {
+			rootapi.Infos.Extensions = make(swagger.Extensions)
+		}
+		rootapi.Infos.Extensions[name] = ext
+		return nil
+	}},
+	{"@Contact", func(value string) error { rootapi.Infos.Contact.EMail = value; return nil }},
+	{"@Name", func(value string) error { rootapi.Infos.Contact.Name = value; return nil }},
+	{"@URL", func(value string) error { rootapi.Infos.Contact.URL = value; return nil }},
+	{"@License", func(value string) error {
+		if rootapi.Infos.License == nil {
+			rootapi.Infos.License = &swagger.License{}
+		}
+		rootapi.Infos.License.Name = value
+		return nil
+	}},
+	{"@LicenseUrl", func(value string) error {
+		if rootapi.Infos.License == nil {
+			rootapi.Infos.License = &swagger.License{}
+		}
+		rootapi.Infos.License.URL = value
+		return nil
+	}},
+	{"@Schemes", func(value string) error { rootapi.Schemes = strings.Split(value, ","); return nil }},
+	{"@Host", func(value string) error { rootapi.Host = value; return nil }},
+	{"@Base", func(value string) error { rootapi.BasePath = value; return nil }},
+	{"@Header", func(value string) error {
+		name, header, err := parseHeader(getparams(value))
+		if err != nil {
+			return err
+		}
+		responseHeaders[name] = header
+		return nil
+	}},
+	{"@SecurityDefinition", parseSecurityDefinition},
+	{"@TagConsumes", func(value string) error { return parseTagContentTypes(tagConsumes, value) }},
+	{"@TagProduces", func(value string) error { return parseTagContentTypes(tagProduces, value) }},
+	{"@TagGroup", parseTagGroup},
+	{"@Security", func(value string) error {
+		security, err := getSecurity(value)
+		if err != nil {
+			return err
+		}
+		if len(rootapi.Security) == 0 {
+			rootapi.Security = make([]map[string][]string, 0)
+		}
+		rootapi.Security = append(rootapi.Security, security)
+		return nil
+	}},
+}
 
+// parseSecurityDefinition parses @SecurityDefinition name type ..., whose fields depend on the type
+func parseSecurityDefinition(value string) error {
+	if len(rootapi.SecurityDefinitions) == 0 {
+		rootapi.SecurityDefinitions = make(map[string]swagger.Security)
+	}
+	var out swagger.Security
+	p := getparams(value)
+	if len(p) < 2 {
+		return fmt.Errorf("not enough params for security: %d", len(p))
+	}
+	out.Type = p[1]
+	switch out.Type {
+	case "oauth2":
+		if len(p) < 6 {
+			return fmt.Errorf("not enough params for oauth2: %d", len(p))
+		}
+		if !(p[3] == "implicit" || p[3] == "password" || p[3] == "application" || p[3] == "accessCode") {
+			return fmt.Errorf("unknown flow type: %s. Possible values are `implicit`, `password`, `application` or `accessCode`", p[3])
+		}
+		out.AuthorizationURL = p[2]
+		out.Flow = p[3]
+		if len(p)%2 != 0 {
+			out.Description = strings.Trim(p[len(p)-1], "\" \t")
+		}
+		for i := 4; i < len(p)-1; i += 2 {
+			out.Scopes = append(out.Scopes, swagger.Scope{Name: p[i], Description: strings.Trim(p[i+1], "\" \t")})
+		}
+	case "apiKey":
+		if len(p) < 4 {
+			return fmt.Errorf("not enough params for apiKey: %d", len(p))
+		}
+		if !(p[3] == "header" || p[3] == "query") {
+			return fmt.Errorf("unknown in type: %s. Possible values are `query` or `header`", p[3])
+		}
+		out.Name = p[2]
+		out.In = p[3]
+		if len(p) > 4 {
+			out.Description = strings.Trim(p[4], "\" \t")
+		}
+	case "basic":
+		if len(p) > 2 {
+			out.Description = strings.Trim(p[2], "\" \t")
+		}
+	default:
+		return fmt.Errorf("unknown security type: %s. Possible values are `oauth2`, `apiKey` or `basic`", p[1])
+	}
+	rootapi.SecurityDefinitions[p[0]] = out
 	return nil
 }
 
 // GenerateDocs generates documentations for a given path.
-func GenerateDocs(curpath string) {
+func GenerateDocs(curpath string) error {
+	if err := buildDocs(curpath); err != nil {
+		return err
+	}
+	dt, dtyml, err := encodeDocs()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Join(curpath, "swagger"), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path.Join(curpath, "swagger", "swagger.json"), dt, 0666); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(curpath, "swagger", "swagger.yml"), dtyml, 0666)
+}
+
+// CheckDocs generates the documentations for a given path in memory and
+// reports whether the swagger.json and swagger.yml files are up to date,
+// logging a summary of the differences otherwise.
+func CheckDocs(curpath string) (bool, error) {
+	if err := buildDocs(curpath); err != nil {
+		return false, err
+	}
+	dt, dtyml, err := encodeDocs()
+	if err != nil {
+		return false, err
+	}
+
+	upToDate := true
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{{"swagger.json", dt}, {"swagger.yml", dtyml}} {
+		old, err := ioutil.ReadFile(path.Join(curpath, "swagger", f.name))
+		if err != nil {
+			beeLogger.Log.Errorf("Cannot read the existing docs: %s", err)
+			upToDate = false
+			continue
+		}
+		if string(old) != string(f.data) {
+			beeLogger.Log.Errorf("swagger/%s is out of date", f.name)
+			upToDate = false
+		}
+	}
+	if !upToDate {
+		logDocsDiff(path.Join(curpath, "swagger", "swagger.json"), dt)
+	}
+	return upToDate, nil
+}
+
+// encodeDocs returns the content of the swagger.json and swagger.yml files
+func encodeDocs() (dt, dtyml []byte, err error) {
+	docs, err := docsValue()
+	if err != nil {
+		return nil, nil, err
+	}
+	if dt, err = json.MarshalIndent(docs, "", "    "); err != nil {
+		return nil, nil, err
+	}
+	if dtyml, err = yaml.Marshal(docs); err != nil {
+		return nil, nil, err
+	}
+	return dt, dtyml, nil
+}
+
+// docsValue returns the value encoded in the docs files, which is bundled in bundle mode
+func docsValue() (interface{}, error) {
+	if Spec != "2.0" && Spec != "3.0" {
+		return nil, fmt.Errorf("unknown spec %s. Possible values are `2.0` or `3.0`", Spec)
+	}
+	if Spec == "3.0" {
+		docs, err := openAPIDocs()
+		if err != nil {
+			return nil, err
+		}
+		if Bundle {
+			docs = bundleDocs(docs)
+		}
+		return withComponents(docs), nil
+	}
+	if Bundle {
+		docs, err := jsonValue(rootapi)
+		if err != nil {
+			return nil, err
+		}
+		return bundleDocs(docs.(map[string]interface{})), nil
+	}
+	return rootapi, nil
+}
+
+// bundleDocs returns the docs where the references to definitions are replaced by the
+// definitions themselves. Recursive definitions can't be inlined, they are kept as references.
+func bundleDocs(docs map[string]interface{}) map[string]interface{} {
+	definitions, _ := docs["definitions"].(map[string]interface{})
+	kept := make(map[string]interface{})
+	for key, value := range docs {
+		if key != "definitions" {
+			docs[key] = inlineRefs(value, definitions, make(map[string]bool), kept)
+		}
+	}
+	// inlining the kept definitions may keep some more
+	for done := false; !done; {
+		done = true
+		for name, def := range kept {
+			if def == nil {
+				kept[name] = inlineRefs(definitions[name], definitions, map[string]bool{name: true}, kept)
+				done = false
+			}
+		}
+	}
+	delete(docs, "definitions")
+	if len(kept) > 0 {
+		docs["definitions"] = kept
+	}
+	return docs
+}
+
+// inlineRefs returns a copy of v where the references to definitions are replaced by the
+// definitions, unless they are being inlined already, in which case they are added to kept
+func inlineRefs(v interface{}, definitions map[string]interface{}, inlining map[string]bool, kept map[string]interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		if ref, ok := t["$ref"].(string); ok && strings.HasPrefix(ref, "#/definitions/") {
+			name := ref[len("#/definitions/"):]
+			def, found := definitions[name]
+			if !found || inlining[name] {
+				if _, ok := kept[name]; found && !ok {
+					beeLogger.Log.Warnf("Definition %s is recursive, it is kept as a reference", name)
+					kept[name] = nil
+				}
+				return t
+			}
+			inlining[name] = true
+			out = inlineRefs(def, definitions, inlining, kept).(map[string]interface{})
+			delete(inlining, name)
+		}
+		for key, value := range t {
+			if key != "$ref" {
+				out[key] = inlineRefs(value, definitions, inlining, kept)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, value := range t {
+			out[i] = inlineRefs(value, definitions, inlining, kept)
+		}
+		return out
+	}
+	return v
+}
+
+// logDocsDiff logs which paths and definitions differ between the existing swagger.json and the generated one
+func logDocsDiff(jsonFile string, generated []byte) {
+	var oldDoc, newDoc map[string]interface{}
+	data, err := ioutil.ReadFile(jsonFile)
+	if err != nil || json.Unmarshal(data, &oldDoc) != nil {
+		return
+	}
+	json.Unmarshal(generated, &newDoc)
+
+	for _, k := range mergeKeys(oldDoc, newDoc) {
+		if k == "paths" || k == "definitions" {
+			oldObjs, _ := oldDoc[k].(map[string]interface{})
+			newObjs, _ := newDoc[k].(map[string]interface{})
+			for _, name := range mergeKeys(oldObjs, newObjs) {
+				logDiff(k+" "+name, oldObjs[name], newObjs[name])
+			}
+			continue
+		}
+		logDiff(k, oldDoc[k], newDoc[k])
+	}
+}
+
+func logDiff(name string, old, new interface{}) {
+	switch {
+	case old == nil:
+		beeLogger.Log.Infof("+ %s", name)
+	case new == nil:
+		beeLogger.Log.Infof("- %s", name)
+	case !reflect.DeepEqual(old, new):
+		beeLogger.Log.Infof("~ %s", name)
+	}
+}
+
+// mergeKeys returns the sorted keys found in any of the maps
+func mergeKeys(maps ...map[string]interface{}) []string {
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if _, ok := seen[k]; !ok {
+				seen[k] = struct{}{}
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// buildDocs analyses the router and controllers of a given path into rootapi.
+func buildDocs(curpath string) error {
 	fset := token.NewFileSet()
 
 	f, err := parser.ParseFile(fset, filepath.Join(curpath, "routers", "router.go"), nil, parser.ParseComments)
 	if err != nil {
-		beeLogger.Log.Fatalf("Error while parsing router.go: %s", err)
+		return fmt.Errorf("error while parsing router.go: %s", err)
+	}
+	if goRoot() == "" {
+		return errors.New("GOROOT environment variable is not set or empty")
+	}
+	for _, ps := range config.Conf.Swagger.PathSecurity {
+		for _, sec := range ps.Security {
+			if _, err := getSecurity(sec); err != nil {
+				return fmt.Errorf("invalid path_security of %s: %s", ps.Path, err)
+			}
+		}
 	}
 
-	rootapi.Infos = swagger.Information{}
+	loadWorkspace(curpath)
+	loadModules(curpath)
+
+	rootapi.Infos = loadInfo(curpath)
+	if err := loadTypes(curpath); err != nil {
+		return err
+	}
 	rootapi.SwaggerVersion = "2.0"
 
 	// Analyse API comments
 	if f.Comments != nil {
 		for _, c := range f.Comments {
-			for _, s := range strings.Split(c.Text(), "\n") {
-				if strings.HasPrefix(s, "@APIVersion") {
-					rootapi.Infos.Version = strings.TrimSpace(s[len("@APIVersion"):])
-				} else if strings.HasPrefix(s, "@Title") {
-					rootapi.Infos.Title = strings.TrimSpace(s[len("@Title"):])
-				} else if strings.HasPrefix(s, "@Description") {
-					rootapi.Infos.Description = strings.TrimSpace(s[len("@Description"):])
-				} else if strings.HasPrefix(s, "@TermsOfServiceUrl") {
-					rootapi.Infos.TermsOfService = strings.TrimSpace(s[len("@TermsOfServiceUrl"):])
-				} else if strings.HasPrefix(s, "@Contact") {
-					rootapi.Infos.Contact.EMail = strings.TrimSpace(s[len("@Contact"):])
-				} else if strings.HasPrefix(s, "@Name") {
-					rootapi.Infos.Contact.Name = strings.TrimSpace(s[len("@Name"):])
-				} else if strings.HasPrefix(s, "@URL") {
-					rootapi.Infos.Contact.URL = strings.TrimSpace(s[len("@URL"):])
-				} else if strings.HasPrefix(s, "@LicenseUrl") {
-					if rootapi.Infos.License == nil {
-						rootapi.Infos.License = &swagger.License{URL: strings.TrimSpace(s[len("@LicenseUrl"):])}
-					} else {
-						rootapi.Infos.License.URL = strings.TrimSpace(s[len("@LicenseUrl"):])
-					}
-				} else if strings.HasPrefix(s, "@License") {
-					if rootapi.Infos.License == nil {
-						rootapi.Infos.License = &swagger.License{Name: strings.TrimSpace(s[len("@License"):])}
-					} else {
-						rootapi.Infos.License.Name = strings.TrimSpace(s[len("@License"):])
-					}
-				} else if strings.HasPrefix(s, "@Schemes") {
-					rootapi.Schemes = strings.Split(strings.TrimSpace(s[len("@Schemes"):]), ",")
-				} else if strings.HasPrefix(s, "@Host") {
-					rootapi.Host = strings.TrimSpace(s[len("@Host"):])
-				} else if strings.HasPrefix(s, "@Base") {
-					rootapi.BasePath = strings.TrimSpace(s[len("@Base"):])
-				} else if strings.HasPrefix(s, "@SecurityDefinition") {
-					if len(rootapi.SecurityDefinitions) == 0 {
-						rootapi.SecurityDefinitions = make(map[string]swagger.Security)
-					}
-					var out swagger.Security
-					p := getparams(strings.TrimSpace(s[len("@SecurityDefinition"):]))
-					if len(p) < 2 {
-						beeLogger.Log.Fatalf("Not enough params for security: %d\n", len(p))
-					}
-					out.Type = p[1]
-					switch out.Type {
-					case "oauth2":
-						if len(p) < 6 {
-							beeLogger.Log.Fatalf("Not enough params for oauth2: %d\n", len(p))
-						}
-						if !(p[3] == "implicit" || p[3] == "password" || p[3] == "application" || p[3] == "accessCode") {
-							beeLogger.Log.Fatalf("Unknown flow type: %s. Possible values are `implicit`, `password`, `application` or `accessCode`.\n", p[1])
-						}
-						out.AuthorizationURL = p[2]
-						out.Flow = p[3]
-						if len(p)%2 != 0 {
-							out.Description = strings.Trim(p[len(p)-1], `" `)
-						}
-						out.Scopes = make(map[string]string)
-						for i := 4; i < len(p)-1; i += 2 {
-							out.Scopes[p[i]] = strings.Trim(p[i+1], `" `)
-						}
-					case "apiKey":
-						if len(p) < 4 {
-							beeLogger.Log.Fatalf("Not enough params for apiKey: %d\n", len(p))
-						}
-						if !(p[3] == "header" || p[3] == "query") {
-							beeLogger.Log.Fatalf("Unknown in type: %s. Possible values are `query` or `header`.\n", p[4])
-						}
-						out.Name = p[2]
-						out.In = p[3]
-						if len(p) > 4 {
-							out.Description = strings.Trim(p[4], `" `)
+			for _, s := range splitLines(c.Text()) {
+				for _, a := range rootAnnotations {
+					if hasAnnotation(s, a.prefix) {
+						if err := a.parse(safeText(strings.TrimSpace(s[len(a.prefix):]))); err != nil {
+							return fmt.Errorf("%s: %s", a.prefix, err)
 						}
-					case "basic":
-						if len(p) > 2 {
-							out.Description = strings.Trim(p[2], `" `)
-						}
-					default:
-						beeLogger.Log.Fatalf("Unknown security type: %s. Possible values are `oauth2`, `apiKey` or `basic`.\n", p[1])
-					}
-					rootapi.SecurityDefinitions[p[0]] = out
-				} else if strings.HasPrefix(s, "@Security") {
-					if len(rootapi.Security) == 0 {
-						rootapi.Security = make([]map[string][]string, 0)
+						break
 					}
-					rootapi.Security = append(rootapi.Security, getSecurity(s))
 				}
 			}
 		}
 	}
+	if APIVersion != "" {
+		rootapi.Infos.Version = APIVersion.String()
+	}
+
 	// Analyse controller package
 	for _, im := range f.Imports {
 		pkgName := ""
@@ -278,8 +705,14 @@ func GenerateDocs(curpath string) {
 			pkgPath := strings.Trim(im.Path.Value, "\"")
 			pkgRealPath := getPackageRealPath(pkgPath)
 			pkgName = getPackageRealName(pkgRealPath)
+			if pkgName == "" {
+				// the package could not be parsed, it is named after the last element of its path
+				pkgName = path.Base(pkgPath)
+			}
+		}
+		if err := analyseControllerPkg(path.Join(curpath, "vendor"), pkgName, im.Path.Value); err != nil {
+			return err
 		}
-		analyseControllerPkg(path.Join(curpath, "vendor"), pkgName, im.Path.Value)
 	}
 	for _, d := range f.Decls {
 		switch specDecl := d.(type) {
@@ -294,11 +727,7 @@ func GenerateDocs(curpath string) {
 							if !selOK || selExpr.Sel.Name != "NewNamespace" {
 								continue
 							}
-							baseurl := ""
-							if len(rootapi.BasePath) > 0 {
-								_, v = findBaseNamespace("", v)
-							}
-							traverseNameSpace(baseurl, v)
+							traverseMount(v)
 						}
 
 					}
@@ -306,36 +735,180 @@ func GenerateDocs(curpath string) {
 			}
 		}
 	}
-	os.Mkdir(path.Join(curpath, "swagger"), 0755)
-	fd, err := os.Create(path.Join(curpath, "swagger", "swagger.json"))
-	if err != nil {
-		panic(err)
+
+	if Strict {
+		if names := emptyDefinitions(); len(names) > 0 {
+			for _, name := range names {
+				beeLogger.Log.Errorf("Definition %s has no properties, document it with @EmptyModel if it is intended", name)
+			}
+			return fmt.Errorf("found %d empty definitions", len(names))
+		}
 	}
-	fdyml, err := os.Create(path.Join(curpath, "swagger", "swagger.yml"))
-	if err != nil {
-		panic(err)
+	if max := config.Conf.Swagger.MaxDefinitions; max > 0 && len(rootapi.Definitions) > max {
+		beeLogger.Log.Warnf("The docs have %d definitions, more than %d, the packages contributing the most to them are %s",
+			len(rootapi.Definitions), max, strings.Join(definitionPackages(5), ", "))
+	}
+	checkTagGroups()
+	renameInstances()
+	return nil
+}
+
+// renameInstances names the definitions of instantiated generic types after their type arguments,
+// e.g. models.Page-models.User for models.Page[models.User], as brackets are not welcome in references
+func renameInstances() {
+	names := make(map[string]string)
+	for name := range rootapi.Definitions {
+		if !strings.Contains(name, "[") {
+			continue
+		}
+		expr, err := parser.ParseExpr(name)
+		if err != nil {
+			continue
+		}
+		if instance := instanceName(expr); instance != name {
+			if _, ok := rootapi.Definitions[instance]; ok {
+				beeLogger.Log.Warnf("Definition %s cannot be named %s, which is already used", name, instance)
+				continue
+			}
+			names[name] = instance
+		}
 	}
-	defer fdyml.Close()
-	defer fd.Close()
-	dt, err := json.MarshalIndent(rootapi, "", "    ")
-	dtyml, erryml := yaml.Marshal(rootapi)
-	if err != nil || erryml != nil {
-		panic(err)
+	if len(names) == 0 {
+		return
+	}
+	for name, instance := range names {
+		def := rootapi.Definitions[name]
+		delete(rootapi.Definitions, name)
+		rootapi.Definitions[instance] = def
+	}
+	renameRefs(reflect.ValueOf(&rootapi).Elem(), names)
+}
+
+// instanceName returns the name of an instantiated generic type, its type arguments following it
+// separated by dashes, e.g. models.Response-array-models.User for models.Response[[]models.User]
+func instanceName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		return instanceName(t.X) + "-" + instanceName(t.Index)
+	case *ast.IndexListExpr:
+		name := instanceName(t.X)
+		for _, index := range t.Indices {
+			name += "-" + instanceName(index)
+		}
+		return name
+	case *ast.ArrayType:
+		return "array-" + instanceName(t.Elt)
+	case *ast.MapType:
+		return "map-" + instanceName(t.Key) + "-" + instanceName(t.Value)
+	case *ast.StarExpr:
+		return instanceName(t.X)
+	}
+	return typeString(expr)
+}
+
+// renameRefs replaces the references to the definitions renamed by names found in v
+func renameRefs(v reflect.Value, names map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			renameRefs(v.Elem(), names)
+		}
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		renameRefs(elem, names)
+		v.Set(elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+			if v.Type().Field(i).Name == "Ref" && field.Kind() == reflect.String {
+				if instance, ok := names[strings.TrimPrefix(field.String(), "#/definitions/")]; ok {
+					field.SetString("#/definitions/" + instance)
+				}
+				continue
+			}
+			renameRefs(field, names)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			renameRefs(v.Index(i), names)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(v.MapIndex(key))
+			if ref, ok := elem.Interface().(string); ok && key.Kind() == reflect.String && key.String() == "$ref" {
+				if instance, ok := names[strings.TrimPrefix(ref, "#/definitions/")]; ok {
+					elem.Set(reflect.ValueOf("#/definitions/" + instance))
+				}
+			} else {
+				renameRefs(elem, names)
+			}
+			v.SetMapIndex(key, elem)
+		}
+	}
+}
+
+// definitionPackages returns the n packages declaring the most definitions along with their
+// number of definitions, e.g. "models (120)"
+func definitionPackages(n int) []string {
+	counts := make(map[string]int)
+	for name := range rootapi.Definitions {
+		// the arguments of a generic type don't count, e.g. models.Page[other.Item] is one of models
+		if i := strings.IndexAny(name, ".["); i > 0 && name[i] == '.' {
+			counts[name[:i]]++
+		}
+	}
+	pkgs := make([]string, 0, len(counts))
+	for pkg := range counts {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		if counts[pkgs[i]] != counts[pkgs[j]] {
+			return counts[pkgs[i]] > counts[pkgs[j]]
+		}
+		return pkgs[i] < pkgs[j]
+	})
+	if len(pkgs) > n {
+		pkgs = pkgs[:n]
+	}
+	for i, pkg := range pkgs {
+		pkgs[i] = fmt.Sprintf("%s (%d)", pkg, counts[pkg])
 	}
-	_, err = fd.Write(dt)
-	_, erryml = fdyml.Write(dtyml)
-	if err != nil || erryml != nil {
-		panic(err)
+	return pkgs
+}
+
+// emptyDefinitions returns the sorted names of the object definitions without any property
+// which are not documented as intentionally empty
+func emptyDefinitions() (names []string) {
+	for name, m := range rootapi.Definitions {
+		if _, ok := basicTypes[name]; ok || emptyModels[name] {
+			continue
+		}
+		if m.Type == astTypeObject && len(m.Properties) == 0 && len(m.AllOf) == 0 && m.Ref == "" && m.Items == nil {
+			names = append(names, name)
+		}
 	}
+	sort.Strings(names)
+	return
 }
 
 func getPackageRealPath(imPath string) string {
-	pkgRealPath := ""
+	pkgRealPath := workspacePackagePath(imPath)
+	if pkgRealPath != "" {
+		return pkgRealPath
+	}
 
 	goPaths := bu.GetGOPATHs()
 	for _, gp := range goPaths {
-		gp, _ = filepath.EvalSymlinks(filepath.Join(gp, "src", imPath))
-		if utils.FileExists(gp) {
+		gp = canonicalPath(filepath.Join(gp, "src", imPath))
+		if gp != "" {
 			pkgRealPath = gp
 			break
 		}
@@ -343,27 +916,298 @@ func getPackageRealPath(imPath string) string {
 	return pkgRealPath
 }
 
-func getPackageRealName(pkgRealPath string) string {
-	pkgRealName := ""
-
-	if pkgRealPath == "" {
-		return ""
+// loadInfo reads the info block of the swaggerinfo.yml file of curpath, if any,
+// the annotations of router.go then override its fields.
+func loadInfo(curpath string) (info swagger.Information) {
+	data, err := ioutil.ReadFile(filepath.Join(curpath, swaggerInfoFile))
+	if err != nil {
+		return
 	}
+	if err := yaml.Unmarshal(data, &info); err != nil {
+		beeLogger.Log.Warnf("Cannot parse %s: %s", swaggerInfoFile, err)
+		return swagger.Information{}
+	}
+	return
+}
 
-	fileSet := token.NewFileSet()
-	f, err := parser.ParseDir(fileSet, pkgRealPath, nil, parser.ParseComments)
+// loadTypes registers the types of the swagger.types.yml file of curpath, if any, as basic types.
+// A type is named after its package, which may be given by its path, e.g. github.com/shopspring/decimal.Decimal,
+// and documented by a swagger type and an optional format, e.g. string:uuid.
+func loadTypes(curpath string) error {
+	// the types of another project documented in the same process are forgotten
+	resetTypes()
+	data, err := ioutil.ReadFile(filepath.Join(curpath, swaggerTypesFile))
 	if err != nil {
-		return ""
+		return nil
 	}
-
-	for pk := range f {
-		if pk == "" {
-			continue
+	var types map[string]string
+	if err := yaml.Unmarshal(data, &types); err != nil {
+		return fmt.Errorf("cannot parse %s: %s", swaggerTypesFile, err)
+	}
+	for name, t := range types {
+		name = name[strings.LastIndex(name, "/")+1:]
+		i := strings.Index(name, ".")
+		if i <= 0 || i == len(name)-1 {
+			return fmt.Errorf("%s: %s should be a type qualified by its package, e.g. decimal.Decimal", swaggerTypesFile, name)
 		}
-		pkgRealName = pk
-		break
+		typeFormat := strings.SplitN(t, ":", 2)
+		if !isScalarType(typeFormat[0]) && typeFormat[0] != astTypeObject {
+			return fmt.Errorf("%s: unknown type %s of %s. Possible values are `string`, `integer`, `number`, `boolean` or `object`", swaggerTypesFile, typeFormat[0], name)
+		}
+		if len(typeFormat) == 1 {
+			t += ":"
+		}
+		basicTypes[name] = t
+		stdlibObject[fmt.Sprintf("&{%s %s}", name[:i], name[i+1:])] = name
 	}
-	return pkgRealName
+	return nil
+}
+
+// resetTypes restores the basic types to the builtin ones
+func resetTypes() {
+	basicTypes = make(map[string]string, len(builtinBasicTypes))
+	for name, t := range builtinBasicTypes {
+		basicTypes[name] = t
+	}
+	stdlibObject = make(map[string]string, len(builtinObjects))
+	for expr, name := range builtinObjects {
+		stdlibObject[expr] = name
+	}
+}
+
+// loadWorkspace looks for the go.work file governing curpath and registers
+// the root of every module it uses, so that their packages can be resolved.
+func loadWorkspace(curpath string) {
+	workFile := os.Getenv("GOWORK")
+	if workFile == "off" {
+		return
+	}
+	if workFile == "" {
+		for dir := curpath; ; dir = filepath.Dir(dir) {
+			if utils.FileExists(filepath.Join(dir, "go.work")) {
+				workFile = filepath.Join(dir, "go.work")
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				return
+			}
+		}
+	}
+	data, err := ioutil.ReadFile(workFile)
+	if err != nil {
+		beeLogger.Log.Warnf("Error while reading '%s': %s", workFile, err)
+		return
+	}
+	for _, dir := range parseModDirective(string(data), "use") {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(workFile), dir)
+		}
+		modData, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			beeLogger.Log.Warnf("Error while reading the go.mod of workspace module '%s': %s", dir, err)
+			continue
+		}
+		if mods := parseModDirective(string(modData), "module"); len(mods) > 0 {
+			workspaceModules[mods[0]] = dir
+		}
+	}
+}
+
+// splitLines splits text into its lines, without the carriage returns of CRLF line endings
+func splitLines(text string) []string {
+	return strings.Split(unixNewlines(text), "\n")
+}
+
+// unixNewlines replaces the CRLF line endings of text by LF ones
+func unixNewlines(text string) string {
+	return strings.Replace(text, "\r\n", "\n", -1)
+}
+
+// safeText normalizes the text of a comment or tag so that both the JSON and the YAML
+// documents stay valid: invalid UTF-8 is replaced, the Unicode line breaks become LF
+// and the control characters but tabs and LF, BOMs included, are dropped.
+func safeText(text string) string {
+	text = strings.ToValidUTF8(unixNewlines(text), "\uFFFD")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case r == '\r' || r == '\u0085' || r == '\u2028' || r == '\u2029':
+			return '\n'
+		case r == '\uFEFF' || unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// parseModDirective returns the first argument of every occurrence of directive in a
+// go.mod or go.work file, such as the module paths of require.
+func parseModDirective(data, directive string) []string {
+	var args []string
+	for _, fields := range modDirectives(data, directive) {
+		args = append(args, fields[0])
+	}
+	return args
+}
+
+// modDirectives returns the arguments of every occurrence of directive in a go.mod
+// or go.work file, in both its single line and parenthesized block forms.
+func modDirectives(data, directive string) [][]string {
+	var args [][]string
+	inBlock := false
+	for _, line := range splitLines(data) {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if inBlock {
+			if fields[0] == ")" {
+				inBlock = false
+			} else {
+				args = append(args, unquoteFields(fields))
+			}
+			continue
+		}
+		if fields[0] != directive || len(fields) < 2 {
+			continue
+		}
+		if fields[1] == "(" {
+			inBlock = true
+		} else {
+			args = append(args, unquoteFields(fields[1:]))
+		}
+	}
+	return args
+}
+
+func unquoteFields(fields []string) []string {
+	for i, f := range fields {
+		fields[i] = strings.Trim(f, `"`)
+	}
+	return fields
+}
+
+// loadModules registers the main module governing curpath when modules are enabled, along
+// with the modules it requires, which are read from the module cache unless they are
+// replaced. The modules of the go.work workspace win over them.
+func loadModules(curpath string) {
+	if os.Getenv("GO111MODULE") == "off" {
+		return
+	}
+	modFile := ""
+	for dir := curpath; modFile == ""; dir = filepath.Dir(dir) {
+		if utils.FileExists(filepath.Join(dir, "go.mod")) {
+			modFile = filepath.Join(dir, "go.mod")
+		} else if filepath.Dir(dir) == dir {
+			return
+		}
+	}
+	data, err := ioutil.ReadFile(modFile)
+	if err != nil {
+		beeLogger.Log.Warnf("Error while reading '%s': %s", modFile, err)
+		return
+	}
+	root := filepath.Dir(modFile)
+	modules := make(map[string]string)
+	if mods := parseModDirective(string(data), "module"); len(mods) > 0 {
+		modules[mods[0]] = root
+	}
+	for _, fields := range modDirectives(string(data), "require") {
+		if len(fields) > 1 {
+			modules[fields[0]] = moduleCacheDir(fields[0], fields[1])
+		}
+	}
+	// replace a [version] => ../a or replace a [version] => b version
+	for _, fields := range modDirectives(string(data), "replace") {
+		arrow := 0
+		for arrow < len(fields) && fields[arrow] != "=>" {
+			arrow++
+		}
+		if arrow == 0 || arrow+1 >= len(fields) {
+			continue
+		}
+		target := fields[arrow+1]
+		if strings.HasPrefix(target, "./") || strings.HasPrefix(target, "../") || filepath.IsAbs(target) {
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(root, target)
+			}
+			modules[fields[0]] = target
+		} else if arrow+2 < len(fields) {
+			modules[fields[0]] = moduleCacheDir(target, fields[arrow+2])
+		}
+	}
+	for mod, dir := range modules {
+		if _, ok := workspaceModules[mod]; !ok {
+			workspaceModules[mod] = dir
+		}
+	}
+}
+
+// moduleCacheDir returns the directory of a version of a module in the module cache, where
+// the upper case letters are escaped, e.g. github.com/!azure/go-autorest for github.com/Azure
+func moduleCacheDir(modPath, version string) string {
+	cache := os.Getenv("GOMODCACHE")
+	if cache == "" {
+		gopaths := bu.GetGOPATHs()
+		if len(gopaths) == 0 {
+			return ""
+		}
+		cache = filepath.Join(gopaths[0], "pkg", "mod")
+	}
+	escape := func(s string) string {
+		var b strings.Builder
+		for _, r := range s {
+			if unicode.IsUpper(r) {
+				b.WriteByte('!')
+				r = unicode.ToLower(r)
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+	return filepath.Join(cache, filepath.FromSlash(escape(modPath)+"@"+escape(version)))
+}
+
+// workspacePackagePath returns the directory of imPath when it belongs to a
+// module of the go.work workspace, preferring the longest matching module path.
+func workspacePackagePath(imPath string) string {
+	modPath := ""
+	for mod := range workspaceModules {
+		if (imPath == mod || strings.HasPrefix(imPath, mod+"/")) && len(mod) > len(modPath) {
+			modPath = mod
+		}
+	}
+	if modPath == "" {
+		return ""
+	}
+	return canonicalPath(filepath.Join(workspaceModules[modPath], strings.TrimPrefix(imPath, modPath)))
+}
+
+func getPackageRealName(pkgRealPath string) string {
+	pkgRealName := ""
+
+	if pkgRealPath == "" {
+		return ""
+	}
+
+	f, err := parseDir(pkgRealPath)
+	if err != nil {
+		return ""
+	}
+
+	for pk := range f {
+		// the external test package of the directory, if any, is not the imported one
+		if pk == "" || (strings.HasSuffix(pk, "_test") && len(f) > 1) {
+			continue
+		}
+		pkgRealName = pk
+		break
+	}
+	return pkgRealName
 }
 
 func findBaseNamespace(url string, pp *ast.CallExpr) (string, *ast.CallExpr) {
@@ -377,8 +1221,7 @@ func findBaseNamespace(url string, pp *ast.CallExpr) (string, *ast.CallExpr) {
 		for _, sp := range params {
 			switch pp := sp.(type) {
 			case *ast.CallExpr:
-				selName := pp.Fun.(*ast.SelectorExpr).Sel.String()
-				if selName == "NSNamespace" {
+				if sel, ok := pp.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "NSNamespace" {
 					if url, node := findBaseNamespace(url+s, pp); node != nil {
 						return url, node
 					}
@@ -389,40 +1232,78 @@ func findBaseNamespace(url string, pp *ast.CallExpr) (string, *ast.CallExpr) {
 	return "", nil
 }
 
-func traverseNameSpace(baseURL string, nsExpr *ast.CallExpr) {
-	s, params := analyseNewNamespace(nsExpr)
-	if len(baseURL) == 0 && len(rootapi.BasePath) == 0 {
-		rootapi.BasePath = s
+// traverseMount documents the operations of a namespace of router.go, the first one giving the
+// base path when there is no @Base. The operations under the base path are relative to it, the
+// others are relative to the prefix of their namespace, which serves them in OpenAPI 3.0 documents.
+func traverseMount(ns *ast.CallExpr) {
+	prefix := ""
+	if len(ns.Args) > 0 {
+		prefix, _ = stringValue(ns.Args[0])
 	}
+	if len(rootapi.BasePath) == 0 {
+		rootapi.BasePath = prefix
+	}
+	_, base := findBaseNamespace("", ns)
+	if base != nil {
+		namespacePrefix = rootapi.BasePath
+		traverseNameSpace("", base)
+		if base == ns {
+			return
+		}
+	}
+	if Spec != "3.0" {
+		if base == nil {
+			beeLogger.Log.Warnf("The namespace %s is outside of the base path %s, it is only documented by -spec=3.0", prefix, rootapi.BasePath)
+		}
+		return
+	}
+	// the base namespace is nested in ns, the other namespaces of ns are mounted on their own
+	baseNamespace = base
+	namespacePrefix = prefix
+	traverseNameSpace("", ns)
+	baseNamespace = nil
+}
+
+func traverseNameSpace(baseURL string, nsExpr *ast.CallExpr) {
+	_, params := analyseNewNamespace(nsExpr)
 
 	for _, sp := range params {
 		switch pp := sp.(type) {
 		case *ast.CallExpr:
-			selname := pp.Fun.(*ast.SelectorExpr).Sel.String()
+			sel, ok := pp.Fun.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			selname := sel.Sel.String()
 			switch selname {
-			case "NSNamespace":
-				url := strings.Trim(pp.Args[0].(*ast.BasicLit).Value, `"`)
-				traverseNameSpace(baseURL+url, pp)
-			case "NSRouter":
-				routeURL := strings.TrimRight(strings.Trim(pp.Args[0].(*ast.BasicLit).Value, "\""), "/")
+			case "NSNamespace", "NSRouter":
+				if len(pp.Args) == 0 {
+					continue
+				}
+				url, ok := stringValue(pp.Args[0])
+				if !ok {
+					beeLogger.Log.Warnf("Cannot resolve the path of %s at %s, it is skipped", selname, typeString(pp.Args[0]))
+					continue
+				}
+				if selname == "NSNamespace" {
+					if pp != baseNamespace {
+						traverseNameSpace(baseURL+url, pp)
+					}
+					continue
+				}
+				routeURL := strings.TrimRight(url, "/")
 				controllerName := analyseNSRouter(baseURL, routeURL, pp)
 				if v, ok := controllerComments[controllerName]; ok {
 					tag := strings.Trim(baseURL, "/")
 					if len(tag) == 0 {
 						tag = "/"
 					}
-					rootapi.Tags = append(rootapi.Tags, swagger.Tag{
-						Name:        tag,
-						Description: v,
-					})
+					describeTag(defaultTag(tag), v)
 				}
 			case "NSInclude":
 				controllerName := analyseNSInclude(baseURL, pp)
 				if v, ok := controllerComments[controllerName]; ok {
-					rootapi.Tags = append(rootapi.Tags, swagger.Tag{
-						Name:        strings.Trim(baseURL, "/"),
-						Description: v,
-					})
+					describeTag(defaultTag(strings.Trim(baseURL, "/")), v)
 				}
 			}
 		}
@@ -433,9 +1314,9 @@ func traverseNameSpace(baseURL string, nsExpr *ast.CallExpr) {
 func analyseNewNamespace(ce *ast.CallExpr) (first string, others []ast.Expr) {
 	for i, p := range ce.Args {
 		if i == 0 {
-			switch pp := p.(type) {
-			case *ast.BasicLit:
-				first = strings.Trim(pp.Value, `"`)
+			var ok bool
+			if first, ok = stringValue(p); !ok {
+				beeLogger.Log.Warnf("Cannot resolve the path of the namespace at %s", typeString(p))
 			}
 			continue
 		}
@@ -444,13 +1325,70 @@ func analyseNewNamespace(ce *ast.CallExpr) (first string, others []ast.Expr) {
 	return
 }
 
+// stringValue returns the value of a string expression of router.go, which may be a literal, a
+// constant or variable of router.go, the constant of an imported package, or their concatenation
+func stringValue(expr ast.Expr) (string, bool) {
+	switch t := expr.(type) {
+	case *ast.BasicLit:
+		if t.Kind == token.STRING {
+			if v, err := strconv.Unquote(t.Value); err == nil {
+				return v, true
+			}
+		}
+	case *ast.ParenExpr:
+		return stringValue(t.X)
+	case *ast.BinaryExpr:
+		if t.Op == token.ADD {
+			x, okX := stringValue(t.X)
+			y, okY := stringValue(t.Y)
+			return x + y, okX && okY
+		}
+	case *ast.Ident:
+		if t.Obj == nil || (t.Obj.Kind != ast.Con && t.Obj.Kind != ast.Var) {
+			return "", false
+		}
+		if vs, ok := t.Obj.Decl.(*ast.ValueSpec); ok {
+			for i, name := range vs.Names {
+				if name.Name == t.Name && i < len(vs.Values) {
+					return stringValue(vs.Values[i])
+				}
+			}
+		}
+	case *ast.SelectorExpr:
+		pkgpath, ok := importlist[fmt.Sprint(t.X)]
+		if !ok {
+			return "", false
+		}
+		pkgs, err := parseDir(getPackageRealPath(pkgpath))
+		if err != nil {
+			return "", false
+		}
+		for _, pkg := range pkgs {
+			for _, fl := range pkg.Files {
+				if obj, ok := fl.Scope.Objects[t.Sel.Name]; ok && obj.Kind == ast.Con {
+					return stringValue(&ast.Ident{Name: t.Sel.Name, Obj: obj})
+				}
+			}
+		}
+	}
+	return "", false
+}
+
 func appendController(x *ast.SelectorExpr, baseurl, routeurl string) string {
 	cname := ""
 	if v, ok := importlist[fmt.Sprint(x.X)]; ok {
 		cname = v + x.Sel.Name
 	}
 	if apis, ok := controllerList[cname]; ok {
+		for _, t := range controllerTags[cname] {
+			if !hasTag(t) {
+				rootapi.Tags = append(rootapi.Tags, swagger.Tag{Name: t})
+			}
+		}
+		controllerInclusions[cname]++
 		for rt, item := range apis {
+			// the controller may be included by several namespaces, each documenting its own operations
+			item = item.Copy()
 			tag := cname
 			if baseurl+routeurl != "" {
 				rt = baseurl + routeurl + rt
@@ -458,37 +1396,273 @@ func appendController(x *ast.SelectorExpr, baseurl, routeurl string) string {
 				if len(tag) == 0 {
 					tag = "/"
 				}
+			} else if config.Conf.Swagger.DefaultTag != "" {
+				tag = ""
+			}
+			if tag = defaultTag(tag); tag == config.Conf.Swagger.DefaultTag && !hasTag(tag) {
+				rootapi.Tags = append(rootapi.Tags, swagger.Tag{Name: tag})
+			}
+			tags := append([]string{tag}, controllerTags[cname]...)
+
+			for _, op := range item.Operations() {
+				op.Tags = tags
+				if n := controllerInclusions[cname]; n > 1 {
+					// each inclusion documents operations of its own, which need ids of their own
+					op.OperationID = includedOperationID(op.OperationID, n)
+				}
+				inheritTagContentTypes(op)
+				mergeResponseContentTypes(op)
+				if strings.Trim(namespacePrefix, "/") != strings.Trim(rootapi.BasePath, "/") && len(op.Servers) == 0 {
+					// mounted outside of the base path
+					op.Servers = serversAt(namespacePrefix)
+				}
+			}
+			if len(rootapi.Paths) == 0 {
+				rootapi.Paths = make(map[string]*swagger.Item)
+			}
+			rt = urlReplace(rt)
+			for _, op := range item.Operations() {
+				if len(op.Security) == 0 {
+					// auth applied by a filter, the annotations win
+					op.Security = pathSecurity(namespacePrefix + rt)
+				}
 			}
+			if existing, ok := rootapi.Paths[rt]; ok {
+				// the path is shared with another controller
+				item = mergeItem(rt, existing, item)
+			}
+			rootapi.Paths[rt] = item
+		}
+	}
+	return cname
+}
 
-			if item.Get != nil {
-				item.Get.Tags = []string{tag}
+// includedOperationID returns the id of an operation documented by the nth inclusion of its controller
+func includedOperationID(id string, n int) string {
+	included := fmt.Sprintf("%s_%d", id, n)
+	if other, ok := operationIDs[included]; ok && other != operationIDs[id] {
+		beeLogger.Log.Warnf("operationId %s is already used by %s", included, other)
+	} else {
+		operationIDs[included] = operationIDs[id]
+	}
+	return included
+}
+
+// mergeItem returns a path item holding the operations of both items, those of src
+// win when both document the same method.
+func mergeItem(rt string, dst, src *swagger.Item) *swagger.Item {
+	merged := *dst
+	for _, m := range []struct {
+		method   string
+		dst, src **swagger.Operation
+	}{
+		{"GET", &merged.Get, &src.Get},
+		{"PUT", &merged.Put, &src.Put},
+		{"POST", &merged.Post, &src.Post},
+		{"DELETE", &merged.Delete, &src.Delete},
+		{"OPTIONS", &merged.Options, &src.Options},
+		{"HEAD", &merged.Head, &src.Head},
+		{"PATCH", &merged.Patch, &src.Patch},
+	} {
+		if *m.src == nil {
+			continue
+		}
+		if *m.dst != nil && *m.dst != *m.src {
+			beeLogger.Log.Warnf("%s %s is documented more than once, keeping the last one", m.method, rt)
+		}
+		*m.dst = *m.src
+	}
+	return &merged
+}
+
+// pathSecurity returns the security requirements configured for the paths matching urlPath
+func pathSecurity(urlPath string) (security []map[string][]string) {
+	for _, ps := range config.Conf.Swagger.PathSecurity {
+		matched, _ := path.Match(ps.Path, urlPath)
+		if !matched && strings.HasSuffix(ps.Path, "/*") {
+			matched = strings.HasPrefix(urlPath, strings.TrimSuffix(ps.Path, "*"))
+		}
+		if matched {
+			for _, s := range ps.Security {
+				// checked by buildDocs already
+				requirement, _ := getSecurity(s)
+				security = append(security, requirement)
 			}
-			if item.Post != nil {
-				item.Post.Tags = []string{tag}
+		}
+	}
+	return
+}
+
+// parseTagContentTypes parses the media types of @TagConsumes or @TagProduces into defaults,
+// e.g. @TagProduces files application/octet-stream,json
+func parseTagContentTypes(defaults map[string][]string, value string) error {
+	p := getparams(value)
+	if len(p) < 2 {
+		return errors.New("a tag and its media types are expected")
+	}
+	for _, t := range strings.Split(p[1], ",") {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		if ct, ok := contentTypeShorthands[t]; ok {
+			t = ct
+		} else if !strings.Contains(t, "/") {
+			return fmt.Errorf("unknown media type %s of tag %s", t, p[0])
+		}
+		defaults[p[0]] = append(defaults[p[0]], t)
+	}
+	return nil
+}
+
+// parseTagGroup parses @TagGroup name tag1,tag2, grouping tags in the navigation of ReDoc,
+// the name is quoted when it has spaces
+func parseTagGroup(value string) error {
+	p := getparams(value)
+	if len(p) < 2 {
+		return errors.New("a group name and its tags are expected")
+	}
+	var tags []string
+	for _, t := range strings.Split(p[1], ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	for i, g := range rootapi.TagGroups {
+		if g.Name == p[0] {
+			rootapi.TagGroups[i].Tags = append(g.Tags, tags...)
+			return nil
+		}
+	}
+	rootapi.TagGroups = append(rootapi.TagGroups, swagger.TagGroup{Name: p[0], Tags: tags})
+	return nil
+}
+
+// checkTagGroups removes the tags of @TagGroup which are neither declared nor used by an operation
+func checkTagGroups() {
+	used := make(map[string]bool)
+	for _, t := range rootapi.Tags {
+		used[t.Name] = true
+	}
+	for _, item := range rootapi.Paths {
+		for _, op := range item.Operations() {
+			for _, t := range op.Tags {
+				used[t] = true
 			}
-			if item.Put != nil {
-				item.Put.Tags = []string{tag}
+		}
+	}
+	for i, g := range rootapi.TagGroups {
+		tags := make([]string, 0, len(g.Tags))
+		for _, t := range g.Tags {
+			if !used[t] {
+				beeLogger.Log.Warnf("@TagGroup %s references the unknown tag %s", g.Name, t)
+				continue
 			}
-			if item.Patch != nil {
-				item.Patch.Tags = []string{tag}
+			tags = append(tags, t)
+		}
+		rootapi.TagGroups[i].Tags = tags
+	}
+}
+
+// contentTypeShorthands are the media types which may be named as in @Accept
+var contentTypeShorthands = map[string]string{
+	"json":        ajson,
+	"xml":         axml,
+	"plain":       aplain,
+	"html":        ahtml,
+	"form":        aform,
+	"urlencoded":  aurl,
+	"json-patch":  ajsonPatch,
+	"merge-patch": amergePatch,
+}
+
+// mergeResponseContentTypes adds the media types of the responses to those produced by op, as
+// Swagger 2.0 has none per response. The responses without any are produced with the ones of op,
+// so these are seeded with the global media types or JSON first.
+func mergeResponseContentTypes(op *swagger.Operation) {
+	op.DeclaredProduces = op.Produces
+	codes := make([]string, 0, len(op.Responses))
+	seed := false
+	for code, rs := range op.Responses {
+		codes = append(codes, code)
+		seed = seed || (rs.Schema != nil && len(rs.ContentTypes) == 0)
+	}
+	sort.Strings(codes)
+	var produces []string
+	for _, code := range codes {
+		for _, ct := range op.Responses[code].ContentTypes {
+			if !hasContentType(produces, ct) {
+				produces = append(produces, ct)
 			}
-			if item.Head != nil {
-				item.Head.Tags = []string{tag}
+		}
+	}
+	if len(produces) > 0 {
+		merged := append([]string(nil), op.Produces...)
+		if len(merged) == 0 && seed {
+			merged = append(merged, rootapi.Produces...)
+			if len(merged) == 0 {
+				merged = []string{ajson}
 			}
-			if item.Delete != nil {
-				item.Delete.Tags = []string{tag}
+		}
+		for _, ct := range produces {
+			if !hasContentType(merged, ct) {
+				merged = append(merged, ct)
 			}
-			if item.Options != nil {
-				item.Options.Tags = []string{tag}
+		}
+		op.Produces = merged
+	}
+	wrapXMLArrays(op)
+}
+
+// inheritTagContentTypes sets the media types of an operation which has none to the defaults
+// of its first tag having some, the request ones only applying to operations with a body
+func inheritTagContentTypes(op *swagger.Operation) {
+	if len(op.Consumes) == 0 && (hasRequestBody(op.Parameters) || len(op.Parts) > 0) {
+		for _, tag := range op.Tags {
+			if consumes, ok := tagConsumes[tag]; ok {
+				op.Consumes = consumes
+				break
 			}
-			if len(rootapi.Paths) == 0 {
-				rootapi.Paths = make(map[string]*swagger.Item)
+		}
+	}
+	if len(op.Produces) == 0 {
+		for _, tag := range op.Tags {
+			if produces, ok := tagProduces[tag]; ok {
+				op.Produces = produces
+				break
 			}
-			rt = urlReplace(rt)
-			rootapi.Paths[rt] = item
 		}
 	}
-	return cname
+}
+
+// defaultTag returns the configured default tag in place of the empty or "/" tag of the root namespace
+func defaultTag(tag string) string {
+	if (tag == "" || tag == "/") && config.Conf.Swagger.DefaultTag != "" {
+		return config.Conf.Swagger.DefaultTag
+	}
+	return tag
+}
+
+// describeTag sets the description of the tag name, which is registered if needed
+func describeTag(name, description string) {
+	for i, t := range rootapi.Tags {
+		if t.Name == name && t.Description == "" {
+			rootapi.Tags[i].Description = description
+			return
+		}
+	}
+	rootapi.Tags = append(rootapi.Tags, swagger.Tag{
+		Name:        name,
+		Description: description,
+	})
+}
+
+func hasTag(name string) bool {
+	for _, t := range rootapi.Tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
 }
 
 func analyseNSRouter(baseurl, routerurl string, ce *ast.CallExpr) string {
@@ -527,13 +1701,13 @@ func analyseNSInclude(baseurl string, ce *ast.CallExpr) string {
 	return cname
 }
 
-func analyseControllerPkg(vendorPath, localName, pkgpath string) {
+func analyseControllerPkg(vendorPath, localName, pkgpath string) error {
 	pkgpath = strings.Trim(pkgpath, "\"")
 	if isSystemPackage(pkgpath) {
-		return
+		return nil
 	}
-	if pkgpath == "github.com/astaxie/beego" {
-		return
+	if isBeegoPackage(pkgpath) {
+		return nil
 	}
 	if localName != "" {
 		importlist[localName] = pkgpath
@@ -541,20 +1715,16 @@ func analyseControllerPkg(vendorPath, localName, pkgpath string) {
 		pps := strings.Split(pkgpath, "/")
 		importlist[pps[len(pps)-1]] = pkgpath
 	}
-	gopaths := bu.GetGOPATHs()
-	if len(gopaths) == 0 {
-		beeLogger.Log.Fatal("GOPATH environment variable is not set or empty")
-	}
 	pkgRealpath := ""
 
-	wg, _ := filepath.EvalSymlinks(filepath.Join(vendorPath, pkgpath))
-	if utils.FileExists(wg) {
+	if wg := canonicalPath(filepath.Join(vendorPath, pkgpath)); wg != "" {
+		pkgRealpath = wg
+	} else if wg = workspacePackagePath(pkgpath); wg != "" {
 		pkgRealpath = wg
 	} else {
-		wgopath := gopaths
-		for _, wg := range wgopath {
-			wg, _ = filepath.EvalSymlinks(filepath.Join(wg, "src", pkgpath))
-			if utils.FileExists(wg) {
+		// legacy projects, outside of any module
+		for _, wg := range bu.GetGOPATHs() {
+			if wg = canonicalPath(filepath.Join(wg, "src", pkgpath)); wg != "" {
 				pkgRealpath = wg
 				break
 			}
@@ -562,68 +1732,214 @@ func analyseControllerPkg(vendorPath, localName, pkgpath string) {
 	}
 	if pkgRealpath != "" {
 		if _, ok := pkgCache[pkgpath]; ok {
-			return
+			return nil
 		}
 		pkgCache[pkgpath] = struct{}{}
 	} else {
-		beeLogger.Log.Fatalf("Package '%s' does not exist in the GOPATH or vendor path", pkgpath)
+		return fmt.Errorf("package '%s' does not exist in the modules, GOPATH or vendor path", pkgpath)
 	}
 
-	fileSet := token.NewFileSet()
-	astPkgs, err := parser.ParseDir(fileSet, pkgRealpath, func(info os.FileInfo) bool {
-		name := info.Name()
-		return !info.IsDir() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".go")
-	}, parser.ParseComments)
+	astPkgs, err := parseDir(pkgRealpath)
 	if err != nil {
-		beeLogger.Log.Fatalf("Error while parsing dir at '%s': %s", pkgpath, err)
+		return fmt.Errorf("error while parsing dir at '%s': %s", pkgpath, err)
 	}
 	for _, pkg := range astPkgs {
+		controllers := controllerTypes(pkg)
+		// Parse controller definition comments first, as their annotations apply to the controller methods
 		for _, fl := range pkg.Files {
 			for _, d := range fl.Decls {
-				switch specDecl := d.(type) {
-				case *ast.FuncDecl:
-					if specDecl.Recv != nil && len(specDecl.Recv.List) > 0 {
-						if t, ok := specDecl.Recv.List[0].Type.(*ast.StarExpr); ok {
-							// Parse controller method
-							parserComments(fl, specDecl, fmt.Sprint(t.X), pkgpath)
-						}
-					}
-				case *ast.GenDecl:
-					if specDecl.Tok == token.TYPE {
-						for _, s := range specDecl.Specs {
-							switch tp := s.(*ast.TypeSpec).Type.(type) {
-							case *ast.StructType:
-								_ = tp.Struct
-								// Parse controller definition comments
-								if strings.TrimSpace(specDecl.Doc.Text()) != "" {
-									controllerComments[pkgpath+s.(*ast.TypeSpec).Name.String()] = specDecl.Doc.Text()
-								}
+				if specDecl, ok := d.(*ast.GenDecl); ok && specDecl.Tok == token.TYPE {
+					for _, s := range specDecl.Specs {
+						switch s.(*ast.TypeSpec).Type.(type) {
+						case *ast.StructType:
+							if !controllers[s.(*ast.TypeSpec).Name.Name] {
+								continue
+							}
+							controllerName := pkgpath + s.(*ast.TypeSpec).Name.String()
+							if doc := parseControllerDoc(controllerName, safeText(specDecl.Doc.Text())); strings.TrimSpace(doc) != "" {
+								controllerComments[controllerName] = doc
 							}
 						}
 					}
 				}
 			}
 		}
-	}
-}
-
-func isSystemPackage(pkgpath string) bool {
-	goroot := os.Getenv("GOROOT")
-	if goroot == "" {
-		goroot = runtime.GOROOT()
-	}
-	if goroot == "" {
-		beeLogger.Log.Fatalf("GOROOT environment variable is not set or empty")
-	}
-
-	wg, _ := filepath.EvalSymlinks(filepath.Join(goroot, "src", "pkg", pkgpath))
-	if utils.FileExists(wg) {
+		for _, fl := range pkg.Files {
+			for _, d := range fl.Decls {
+				if specDecl, ok := d.(*ast.FuncDecl); ok && specDecl.Recv != nil && len(specDecl.Recv.List) > 0 {
+					// Parse controller method, whether its receiver is a pointer or a value
+					var recv string
+					switch t := specDecl.Recv.List[0].Type.(type) {
+					case *ast.StarExpr:
+						recv = fmt.Sprint(t.X)
+					case *ast.Ident:
+						recv = t.Name
+					}
+					if !controllers[recv] {
+						continue
+					}
+					if err := parserComments(fl, specDecl, recv, pkgpath); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isBeegoPackage reports whether pkgpath is a package of beego v1 or v2, which holds no controller
+// of the application but the Controller they embed, e.g. github.com/beego/beego/v2/server/web
+func isBeegoPackage(pkgpath string) bool {
+	for _, root := range []string{"github.com/astaxie/beego", "github.com/beego/beego/v2"} {
+		if pkgpath == root || strings.HasPrefix(pkgpath, root+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// controllerPackages lists the packages of beego v1 and v2 declaring the Controller
+var controllerPackages = map[string]bool{
+	"github.com/astaxie/beego":             true,
+	"github.com/beego/beego/v2/server/web": true,
+}
+
+// controllerTypes returns the names of the controllers of a package, the struct types embedding
+// the Controller of beego, such as beego.Controller or web.Controller, or another controller
+// of the package.
+func controllerTypes(pkg *ast.Package) map[string]bool {
+	embedded := make(map[string][]string) //struct type:types of the package it embeds
+	controllers := make(map[string]bool)
+	for _, fl := range pkg.Files {
+		imports := make(map[string]string) //local name:package path
+		for _, im := range fl.Imports {
+			p := strings.Trim(im.Path.Value, `"`)
+			name := path.Base(p)
+			if isMajorVersion(name) {
+				name = path.Base(path.Dir(p))
+			}
+			if im.Name != nil {
+				name = im.Name.Name
+			}
+			imports[name] = p
+		}
+		for _, d := range fl.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				embedded[ts.Name.Name] = nil
+				for _, field := range st.Fields.List {
+					if field.Names != nil {
+						continue
+					}
+					typ := field.Type
+					if star, ok := typ.(*ast.StarExpr); ok {
+						typ = star.X
+					}
+					switch t := typ.(type) {
+					case *ast.Ident:
+						embedded[ts.Name.Name] = append(embedded[ts.Name.Name], t.Name)
+					case *ast.SelectorExpr:
+						x, ok := t.X.(*ast.Ident)
+						if !ok {
+							continue
+						}
+						if controllerPackages[imports[x.Name]] && t.Sel.Name == "Controller" {
+							controllers[ts.Name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, types := range embedded {
+			for _, t := range types {
+				if controllers[t] && !controllers[name] {
+					controllers[name] = true
+					changed = true
+				}
+			}
+		}
+	}
+	return controllers
+}
+
+// isMajorVersion reports whether the element of an import path is the major version of a module, e.g. v2
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(elem[1:])
+	return err == nil
+}
+
+// parseControllerDoc collects the annotations of a controller doc comment
+// and returns the remaining text, which describes the controller.
+// @Tags admin,users	adds tags to all operations of the controller
+// @DefaultResponse models.Response	is the model of the @Success responses declared without schema
+// @Deprecated	deprecates all operations of the controller, but those having @Deprecated false
+func parseControllerDoc(controllerName, doc string) string {
+	var lines []string
+	for _, line := range splitLines(doc) {
+		t := strings.TrimSpace(line)
+		if hasAnnotation(t, "@Deprecated") {
+			if value := strings.TrimSpace(t[len("@Deprecated"):]); value == "" {
+				deprecatedControllers[controllerName] = true
+			} else if deprecated, err := strconv.ParseBool(value); err != nil {
+				beeLogger.Log.Warnf("Invalid @Deprecated of controller %s: %s", controllerName, value)
+			} else {
+				deprecatedControllers[controllerName] = deprecated
+			}
+			continue
+		}
+		if hasAnnotation(t, "@DefaultResponse") {
+			controllerDefaultResponses[controllerName] = strings.TrimSpace(t[len("@DefaultResponse"):])
+			continue
+		}
+		if hasAnnotation(t, "@Tags") {
+			for _, tag := range strings.Split(strings.TrimSpace(t[len("@Tags"):]), ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					controllerTags[controllerName] = append(controllerTags[controllerName], tag)
+				}
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// goRoot returns the root of the Go tree, which holds the packages of the standard library
+func goRoot() string {
+	if goroot := os.Getenv("GOROOT"); goroot != "" {
+		return goroot
+	}
+	return runtime.GOROOT()
+}
+
+func isSystemPackage(pkgpath string) bool {
+	// the first element of the path of a module is a domain name, unlike the standard library
+	if strings.Contains(strings.SplitN(pkgpath, "/", 2)[0], ".") {
+		return false
+	}
+	goroot := goRoot()
+
+	if canonicalPath(filepath.Join(goroot, "src", "pkg", pkgpath)) != "" {
 		return true
 	}
 
 	//TODO(zh):support go1.4
-	wg, _ = filepath.EvalSymlinks(filepath.Join(goroot, "src", pkgpath))
-	return utils.FileExists(wg)
+	return canonicalPath(filepath.Join(goroot, "src", pkgpath)) != ""
 }
 
 func peekNextSplitString(ss string) (s string, spacePos int) {
@@ -637,19 +1953,796 @@ func peekNextSplitString(ss string) (s string, spacePos int) {
 	return
 }
 
+// operationComments holds the state of the comments of a controller method being parsed
+type operationComments struct {
+	fl             *ast.File
+	controllerName string
+	pkgpath        string
+	funcName       string
+	routes         []route //set by @router, a method may serve several paths
+	httpMethod     string  //method of the operation when it is named after it, without @router
+	opts           swagger.Operation
+	funcParamMap   map[string]string
+	links          map[string]map[string]swagger.Link
+	headers        map[string]map[string]swagger.Header //response code:header name:header
+	explicitID     string                               //set by @OperationId, wins over the id derived from @Title
+	hasDeprecated  bool                                 //set by @Deprecated, wins over the @Deprecated of the controller
+}
+
+// operationAnnotation parses the annotation of a controller method starting with prefix
+type operationAnnotation struct {
+	prefix string
+	parse  func(c *operationComments, value string) error
+}
+
+// operationAnnotations lists the annotations of controller methods, init sorts them so that
+// the longest prefixes are matched first
+var operationAnnotations = []operationAnnotation{
+	{"@router", (*operationComments).router},
+	{"@Title", (*operationComments).title},
+	{"@OperationId", (*operationComments).operationID},
+	{"@Description", (*operationComments).description},
+	{"@Description.File", (*operationComments).descriptionFile},
+	{"@Summary", (*operationComments).summary},
+	{"@Success", (*operationComments).success},
+	{"@Param", (*operationComments).param},
+	{"@Failure", (*operationComments).failure},
+	{"@Deprecated", (*operationComments).deprecated},
+	{"@Sunset", (*operationComments).sunset},
+	{"@MaxBodySize", (*operationComments).maxBodySize},
+	{"@CodeSample", (*operationComments).codeSample},
+	{"@Accept", (*operationComments).accept},
+	{"@Link", (*operationComments).link},
+	{"@Header", (*operationComments).header},
+	{"@Part", (*operationComments).part},
+	{"@Callback", (*operationComments).callback},
+	{"@Server", (*operationComments).server},
+	{"@Security", (*operationComments).security},
+}
+
+// hasAnnotation reports whether the comment t starts with the whole annotation, which is
+// followed by a space or ends the comment, so that @Security does not match @SecurityDefinition
+func hasAnnotation(t, annotation string) bool {
+	if !strings.HasPrefix(t, annotation) {
+		return false
+	}
+	rest := t[len(annotation):]
+	return rest == "" || unicode.IsSpace(rune(rest[0]))
+}
+
+// parse hands a comment line to the parser of its annotation, the registered custom ones included
+func (c *operationComments) parse(t string) error {
+	for _, a := range operationAnnotations {
+		if hasAnnotation(t, a.prefix) {
+			return a.parse(c, strings.TrimSpace(t[len(a.prefix):]))
+		}
+	}
+	if strings.HasPrefix(t, "@") {
+		name, pos := peekNextSplitString(t)
+		if handler, ok := annotationHandlers[name]; ok {
+			if c.opts.Extensions == nil {
+				c.opts.Extensions = make(swagger.Extensions)
+			}
+			handler(&c.opts, strings.TrimSpace(t[pos:]))
+		}
+	}
+	return nil
+}
+
+// router parses @router
+func (c *operationComments) router(value string) error {
+	// the path and the methods may be separated by spaces or tabs
+	routerPath, pos := peekNextSplitString(value)
+	if routerPath == "" {
+		return errors.New("you should has router infomation")
+	}
+	rt := route{path: routerPath, methods: "GET"}
+	if methods, _ := peekNextSplitString(strings.TrimSpace(value[pos:])); methods != "" {
+		rt.methods = strings.ToUpper(strings.Trim(methods, "[]"))
+	}
+	c.routes = append(c.routes, rt)
+	return nil
+}
+
+// route is a path served by an operation with the comma-separated HTTP methods
+type route struct {
+	path    string
+	methods string
+}
+
+// title parses @Title
+func (c *operationComments) title(value string) error {
+	id := sanitizeOperationID(value)
+	if id != value {
+		beeLogger.Log.Warnf("[%s.%s] @Title '%s' is not a valid operationId, using '%s'", c.controllerName, c.funcName, value, id)
+	}
+	c.opts.OperationID = c.controllerName + "." + id
+	return nil
+}
+
+// operationID parses @OperationId, whose id is used verbatim, e.g. to keep the method names of
+// existing client SDKs
+func (c *operationComments) operationID(value string) error {
+	if value == "" {
+		beeLogger.Log.Warnf("[%s.%s] @OperationId should be followed by the id", c.controllerName, c.funcName)
+		return nil
+	}
+	c.explicitID = value
+	return nil
+}
+
+// descriptionFile parses @Description.File
+func (c *operationComments) descriptionFile(value string) error {
+	descFile := value
+	if !filepath.IsAbs(descFile) {
+		descFile = filepath.Join(fileDir(c.fl), descFile)
+	}
+	desc, err := ioutil.ReadFile(descFile)
+	if err != nil {
+		beeLogger.Log.Warnf("[%s.%s] Cannot read the description file: %s", c.controllerName, c.funcName, err)
+		return nil
+	}
+	c.opts.Description += fmt.Sprintf("%s\n\n", strings.TrimSpace(safeText(string(desc))))
+	return nil
+}
+
+// description parses @Description
+func (c *operationComments) description(value string) error {
+	c.opts.Description += fmt.Sprintf("%s\n\n", strings.Trim(value, "\""))
+	return nil
+}
+
+// summary parses @Summary
+func (c *operationComments) summary(value string) error {
+	c.opts.Summary = value
+	return nil
+}
+
+// success parses @Success
+func (c *operationComments) success(value string) error {
+	rs := swagger.Response{}
+	ss := value
+	respCode, pos := peekNextSplitString(ss)
+	ss = strings.TrimSpace(ss[pos:])
+	respType, pos := peekNextSplitString(ss)
+	if respType == "{object}" || respType == "{array}" {
+		isArray := respType == "{array}"
+		ss = strings.TrimSpace(ss[pos:])
+		schemaName, pos := peekNextSplitString(ss)
+		if schemaName == "" {
+			return fmt.Errorf("[%s.%s] Schema must follow {object} or {array}", c.controllerName, c.funcName)
+		}
+		rs.Schema = responseSchema(c.fl, c.pkgpath, c.controllerName, schemaName, isArray)
+		rs.Description = strings.TrimSpace(ss[pos:])
+	} else if respType == "{file}" {
+		// a download, whose media type is a generic binary one unless documented
+		rs.Schema = &swagger.Schema{
+			Type:   "string",
+			Format: "binary",
+		}
+		rs.Description = strings.TrimSpace(ss[pos:])
+	} else if schema, ok := scalarSchema(respType); ok {
+		// a scalar such as {string:date-time}
+		rs.Schema = schema
+		rs.Description = strings.TrimSpace(ss[pos:])
+	} else {
+		if model, ok := controllerDefaultResponses[c.pkgpath+c.controllerName]; ok {
+			rs.Schema = responseSchema(c.fl, c.pkgpath, c.controllerName, model, false)
+		}
+		rs.Description = strings.TrimSpace(ss)
+	}
+	// the options of the schema may be given before or after the content types
+	options := make(map[string]string)
+	rs.Description = responseOptions(rs.Description, options)
+	rs.Description, rs.ContentTypes = splitContentTypes(rs.Description)
+	rs.Description = responseOptions(rs.Description, options)
+	if respType == "{file}" && len(rs.ContentTypes) == 0 {
+		rs.ContentTypes = []string{aoctet}
+	}
+	if len(options) > 0 && rs.Schema == nil {
+		beeLogger.Log.Warnf("[%s.%s] The response %s has no schema for its options", c.controllerName, c.funcName, respCode)
+	} else if len(options) > 0 {
+		c.setResponseOptions(&rs, respCode, options)
+	}
+	c.opts.Responses[respCode] = rs
+	return nil
+}
+
+// responseOptionKeys lists the options which may trail the description of a @Success as key=value,
+// the value being quoted when it has spaces
+// @Success 201 {string:date-time} "timestamp" example=2026-01-02T15:04:05Z
+// @Success 200 {object} models.User "the owner" title=Owner description="The owner of the account"
+var responseOptionKeys = map[string]bool{
+	"example":     true,
+	"title":       true,
+	"description": true,
+}
+
+// responseOptions moves the trailing options of a response description into options,
+// returning the rest of the description
+func responseOptions(desc string, options map[string]string) string {
+	for {
+		desc = strings.TrimSpace(desc)
+		end, value := len(desc), ""
+		if strings.HasSuffix(desc, `"`) && len(desc) > 1 {
+			end = strings.LastIndex(desc[:len(desc)-1], `"`)
+			if end < 0 {
+				return desc
+			}
+			value = desc[end+1 : len(desc)-1]
+		}
+		start := strings.LastIndexFunc(desc[:end], unicode.IsSpace) + 1
+		token := desc[start:end]
+		if end == len(desc) {
+			j := strings.IndexAny(token, "=:")
+			if j < 0 {
+				return desc
+			}
+			token, value = token[:j+1], token[j+1:]
+		}
+		key := strings.TrimRight(token, "=:")
+		if len(token) != len(key)+1 || !responseOptionKeys[key] {
+			return desc
+		}
+		if _, ok := options[key]; !ok {
+			options[key] = value
+		}
+		desc = desc[:start]
+	}
+}
+
+// setResponseOptions applies the options of a response to its schema. The schema of a definition
+// is wrapped so that its title and description are not those of the definition.
+func (c *operationComments) setResponseOptions(rs *swagger.Response, respCode string, options map[string]string) {
+	if example, ok := options["example"]; ok {
+		if !isScalarType(rs.Schema.Type) {
+			beeLogger.Log.Warnf("[%s.%s] example is only allowed for scalar responses: %s", c.controllerName, c.funcName, respCode)
+		} else if rs.Schema.Type == "string" {
+			rs.Schema.Example = example
+		} else {
+			var value interface{}
+			if err := json.Unmarshal([]byte(example), &value); err != nil {
+				beeLogger.Log.Warnf("[%s.%s] The example %s of response %s is not a valid %s", c.controllerName, c.funcName, example, respCode, rs.Schema.Type)
+			} else {
+				rs.Schema.Example = value
+			}
+		}
+	}
+	title, hasTitle := options["title"]
+	description, hasDescription := options["description"]
+	if !hasTitle && !hasDescription {
+		return
+	}
+	if rs.Schema.Ref != "" {
+		rs.Schema = &swagger.Schema{AllOf: []*swagger.Schema{{Ref: rs.Schema.Ref}}}
+	}
+	if hasTitle {
+		rs.Schema.Title = title
+	}
+	if hasDescription {
+		rs.Schema.Description = description
+	}
+}
+
+// wrapXMLArrays names the element wrapping the items of the array responses of an operation
+// producing xml, which needs one. Plurals can't be guessed from the items, it is named items.
+func wrapXMLArrays(op *swagger.Operation) {
+	if !hasContentType(op.Produces, axml) {
+		return
+	}
+	for code, rs := range op.Responses {
+		if rs.Schema != nil && rs.Schema.Type == astTypeArray && rs.Schema.XML == nil && rs.Schema.Items != nil {
+			schema := *rs.Schema
+			schema.XML = &swagger.XML{Name: "items", Wrapped: true}
+			rs.Schema = &schema
+			op.Responses[code] = rs
+		}
+	}
+}
+
+// scalarSchema returns the schema of a scalar response type, a basic type or a swagger one
+// between braces whose format may follow a colon, e.g. {int64}, {string:date-time} or {integer:int32}
+func scalarSchema(respType string) (*swagger.Schema, bool) {
+	if !strings.HasPrefix(respType, "{") || !strings.HasSuffix(respType, "}") {
+		return nil, false
+	}
+	typeFormat := strings.SplitN(respType[1:len(respType)-1], ":", 2)
+	schema := &swagger.Schema{}
+	if sType, ok := basicTypes[typeFormat[0]]; ok {
+		basic := strings.Split(sType, ":")
+		schema.Type = basic[0]
+		schema.Format = basic[1]
+	} else if isScalarType(typeFormat[0]) {
+		schema.Type = typeFormat[0]
+	} else {
+		return nil, false
+	}
+	if !isScalarType(schema.Type) {
+		return nil, false
+	}
+	if len(typeFormat) == 2 {
+		schema.Format = typeFormat[1]
+	}
+	return schema, true
+}
+
+// param parses @Param
+func (c *operationComments) param(value string) error {
+	para := swagger.Parameter{}
+	p := getparams(value)
+	if len(p) < 4 {
+		return errors.New(c.controllerName + "_" + c.funcName + "'s comments @Param should have at least 4 params")
+	}
+	p, paramOpts := paramOptions(p)
+	paramNames := strings.SplitN(p[0], "=>", 2)
+	para.Name = paramNames[0]
+	funcParamName := para.Name
+	if len(paramNames) > 1 {
+		funcParamName = paramNames[1]
+	}
+	paramType, ok := c.funcParamMap[funcParamName]
+	if ok {
+		delete(c.funcParamMap, funcParamName)
+	} else if len(paramNames) > 1 {
+		beeLogger.Log.Warnf("[%s.%s] @Param %s refers to the unknown function parameter '%s'", c.controllerName, c.funcName, para.Name, funcParamName)
+	}
+
+	switch p[1] {
+	case "query":
+		fallthrough
+	case "header":
+		fallthrough
+	case "path":
+		fallthrough
+	case "formData":
+		fallthrough
+	case "body":
+		break
+	default:
+		beeLogger.Log.Warnf("[%s.%s] Unknown param location: %s. Possible values are `query`, `header`, `path`, `formData` or `body`.\n", c.controllerName, c.funcName, p[1])
+	}
+	para.In = p[1]
+	pp := strings.Split(p[2], ".")
+	typ := pp[len(pp)-1]
+	if strings.HasPrefix(p[2], "{") {
+		para.Schema = inlineSchema(p[2])
+	} else if _, isMap := mapValueType(p[2]); isMap && p[1] == "body" {
+		para.Schema = responseSchema(c.fl, c.pkgpath, c.controllerName, p[2], false)
+	} else if _, isBasic := basicTypes[strings.TrimPrefix(p[2], "[]")]; isBasic && len(pp) >= 2 {
+		// a type of the standard library such as time.Duration, which is no model
+		setParamType(&para, p[2], c.fl, c.pkgpath, c.controllerName)
+	} else if len(pp) >= 2 {
+		isArray := false
+		if p[1] == "body" && strings.HasPrefix(p[2], "[]") {
+			p[2] = p[2][2:]
+			isArray = true
+		}
+		defined := hasDefinition(p[2])
+		m, mod, realTypes := getModel(c.fl, p[2])
+		if p[1] != "body" && isScalarType(mod.Type) {
+			// only body params may refer to a definition, so the type, enums included, is inlined
+			para.Type = mod.Type
+			para.Format = mod.Format
+			para.Enum = enumValues(mod)
+			if !defined {
+				delete(rootapi.Definitions, m)
+			}
+		} else if p[1] == "query" && mod.Type == astTypeObject {
+			// a struct gathering query params, each of its fields is one of them
+			c.opts.Parameters = append(c.opts.Parameters, queryParams(c.fl, mod, make(map[string]bool))...)
+			return nil
+		} else if isArray {
+			para.Schema = &swagger.Schema{
+				Type: astTypeArray,
+				Items: &swagger.Schema{
+					Ref: "#/definitions/" + m,
+				},
+			}
+		} else {
+			para.Schema = &swagger.Schema{
+				Ref: "#/definitions/" + m,
+			}
+		}
+
+		// an inlined type refers to no definition
+		if para.Schema != nil {
+			if _, ok := modelsList[c.pkgpath+c.controllerName]; !ok {
+				modelsList[c.pkgpath+c.controllerName] = make(map[string]swagger.Schema)
+			}
+			modelsList[c.pkgpath+c.controllerName][typ] = mod
+			appendModels(c.fl, c.pkgpath, c.controllerName, realTypes)
+		}
+	} else {
+		if typ == "auto" {
+			typ = paramType
+		}
+		setParamType(&para, typ, c.fl, c.pkgpath, c.controllerName)
+	}
+	if format, ok := paramOpts["collectionFormat"]; ok {
+		if para.Type != astTypeArray {
+			beeLogger.Log.Warnf("[%s.%s] collectionFormat is only allowed for array params: %s", c.controllerName, c.funcName, para.Name)
+		} else if !(format == "csv" || format == "ssv" || format == "tsv" || format == "pipes" ||
+			(format == "multi" && (para.In == "query" || para.In == "formData"))) {
+			beeLogger.Log.Warnf("[%s.%s] Unknown collectionFormat for %s param %s: %s", c.controllerName, c.funcName, para.In, para.Name, format)
+		} else {
+			para.CollectionFormat = format
+		}
+	} else if para.In == "formData" && para.Type == astTypeArray {
+		// each value is sent as its own form field
+		para.CollectionFormat = "multi"
+	}
+	para.Required, _ = strconv.ParseBool(p[3])
+	if allow, ok := paramOpts["allowEmptyValue"]; ok {
+		if para.In != "query" && para.In != "formData" {
+			beeLogger.Log.Warnf("[%s.%s] allowEmptyValue is only allowed for query and formData params: %s", c.controllerName, c.funcName, para.Name)
+		} else if v, err := strconv.ParseBool(allow); err != nil {
+			beeLogger.Log.Warnf("[%s.%s] Invalid allowEmptyValue for param %s: %s", c.controllerName, c.funcName, para.Name, allow)
+		} else {
+			para.AllowEmptyValue = v
+		}
+	} else if para.In == "query" || para.In == "formData" {
+		// optional params may be sent without any value unless told otherwise
+		para.AllowEmptyValue = !para.Required
+	}
+	paramDesc := strings.Trim(p[4], "\" \t")
+	lines := strings.Split(paramDesc, `\n`)
+	for _, line := range lines {
+		para.Description = fmt.Sprintf("%s\n%s", para.Description, line)
+	}
+
+	if len(p) >= 6 {
+		para.Default = str2RealType(p[5], para.Type)
+	}
+
+	if len(p) >= 7 {
+		values := strings.Split(p[6], ":")
+		if len(values) > 0 {
+			para.Enum = make([]interface{}, 0, len(values))
+		}
+		for _, value := range values {
+			para.Enum = append(para.Enum, str2RealType(value, para.Type))
+		}
+	}
+	example, hasExample := paramOpts["example"]
+	if strings.HasPrefix(example, "file:") {
+		example, hasExample = c.exampleFile(para.Name, example[len("file:"):])
+	}
+	if hasExample {
+		if para.Schema != nil {
+			var value interface{}
+			if err := json.Unmarshal([]byte(example), &value); err != nil {
+				value = example
+			}
+			for _, mismatch := range exampleMismatches(*para.Schema, value, "") {
+				beeLogger.Log.Warnf("[%s.%s] The example of param %s does not match its schema: %s", c.controllerName, c.funcName, para.Name, mismatch)
+			}
+			para.Schema.Example = value
+		} else {
+			para.Example = str2RealType(example, para.Type)
+		}
+	}
+	if para.Default != nil && len(para.Enum) > 0 && !inEnum(para.Default, para.Enum) {
+		beeLogger.Log.Warnf("[%s.%s] The default value %v of param %s is not one of its enum values", c.controllerName, c.funcName, para.Default, para.Name)
+	}
+
+	c.opts.Parameters = append(c.opts.Parameters, para)
+	return nil
+}
+
+// exampleFile returns the content of the file holding the example of the param name,
+// relative to the controller
+func (c *operationComments) exampleFile(name, exampleFile string) (string, bool) {
+	if !filepath.IsAbs(exampleFile) {
+		exampleFile = filepath.Join(fileDir(c.fl), exampleFile)
+	}
+	data, err := ioutil.ReadFile(exampleFile)
+	if err != nil {
+		beeLogger.Log.Warnf("[%s.%s] Cannot read the example file of param %s: %s", c.controllerName, c.funcName, name, err)
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// failure parses @Failure
+func (c *operationComments) failure(value string) error {
+	rs := swagger.Response{}
+	var cd []rune
+	var start bool
+	for i, s := range value {
+		if unicode.IsSpace(s) {
+			if start {
+				rs.Description = strings.TrimSpace(value[i+1:])
+				break
+			} else {
+				continue
+			}
+		}
+		start = true
+		cd = append(cd, s)
+	}
+	rs.Description, rs.ContentTypes = splitContentTypes(rs.Description)
+	c.opts.Responses[string(cd)] = rs
+	return nil
+}
+
+// deprecated parses @Deprecated, which deprecates the operation without any value as it does
+// a controller
+func (c *operationComments) deprecated(value string) error {
+	if value == "" {
+		c.opts.Deprecated = true
+	} else if deprecated, err := strconv.ParseBool(value); err != nil {
+		beeLogger.Log.Warnf("[%s.%s] Invalid @Deprecated: %s", c.controllerName, c.funcName, value)
+		return nil
+	} else {
+		c.opts.Deprecated = deprecated
+	}
+	c.hasDeprecated = true
+	return nil
+}
+
+// maxBodySize parses @MaxBodySize, the size in bytes of the largest request body accepted
+func (c *operationComments) maxBodySize(value string) error {
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		beeLogger.Log.Warnf("[%s.%s] Invalid @MaxBodySize: %s. Use a number of bytes like 10485760.", c.controllerName, c.funcName, value)
+		return nil
+	}
+	if c.opts.Extensions == nil {
+		c.opts.Extensions = make(swagger.Extensions)
+	}
+	c.opts.Extensions["x-max-body-size"] = size
+	return nil
+}
+
+// sunset parses @Sunset
+func (c *operationComments) sunset(value string) error {
+	if !isValidDate(value) {
+		beeLogger.Log.Warnf("[%s.%s] Invalid @Sunset date: %s. Use a date like 2006-01-02 or an HTTP-date.", c.controllerName, c.funcName, value)
+		return nil
+	}
+	if c.opts.Extensions == nil {
+		c.opts.Extensions = make(swagger.Extensions)
+	}
+	c.opts.Extensions["x-sunset"] = value
+	return nil
+}
+
+// codeSample parses @CodeSample
+func (c *operationComments) codeSample(value string) error {
+	// @CodeSample curl ./samples/get_user.sh, or the source itself
+	lang, pos := peekNextSplitString(value)
+	source := strings.TrimSpace(value[pos:])
+	if lang == "" || source == "" {
+		beeLogger.Log.Warnf("[%s.%s] @CodeSample should have a language and a source", c.controllerName, c.funcName)
+		return nil
+	}
+	sampleFile := source
+	if !filepath.IsAbs(sampleFile) {
+		sampleFile = filepath.Join(fileDir(c.fl), sampleFile)
+	}
+	if data, err := ioutil.ReadFile(sampleFile); err == nil {
+		source = unixNewlines(string(data))
+	}
+	if c.opts.Extensions == nil {
+		c.opts.Extensions = make(swagger.Extensions)
+	}
+	samples, _ := c.opts.Extensions["x-codeSamples"].([]swagger.CodeSample)
+	c.opts.Extensions["x-codeSamples"] = append(samples, swagger.CodeSample{Lang: lang, Source: source})
+	return nil
+}
+
+// accept parses @Accept
+func (c *operationComments) accept(value string) error {
+	accepts := strings.Split(value, ",")
+	for _, a := range accepts {
+		a = strings.TrimSpace(a)
+		switch a {
+		case "json":
+			c.opts.Consumes = append(c.opts.Consumes, ajson)
+			c.opts.Produces = append(c.opts.Produces, ajson)
+		case "xml":
+			c.opts.Consumes = append(c.opts.Consumes, axml)
+			c.opts.Produces = append(c.opts.Produces, axml)
+		case "plain":
+			c.opts.Consumes = append(c.opts.Consumes, aplain)
+			c.opts.Produces = append(c.opts.Produces, aplain)
+		case "html":
+			c.opts.Consumes = append(c.opts.Consumes, ahtml)
+			c.opts.Produces = append(c.opts.Produces, ahtml)
+		case "form":
+			c.opts.Consumes = append(c.opts.Consumes, aform)
+		case "urlencoded":
+			c.opts.Consumes = append(c.opts.Consumes, aurl)
+		case "json-patch":
+			c.opts.Consumes = append(c.opts.Consumes, ajsonPatch)
+		case "merge-patch":
+			c.opts.Consumes = append(c.opts.Consumes, amergePatch)
+		default:
+			// a full media type lists one more content type the body may be sent as
+			if strings.Contains(a, "/") {
+				c.opts.Consumes = append(c.opts.Consumes, a)
+			}
+		}
+	}
+	return nil
+}
+
+// link parses @Link
+func (c *operationComments) link(value string) error {
+	// @Link 201 GetUser operationId=UserController.Get params=uid:$response.body#/id "description"
+	p := getparams(value)
+	if len(p) < 3 {
+		return fmt.Errorf("[%s.%s] @Link should have at least 3 params", c.controllerName, c.funcName)
+	}
+	link := swagger.Link{}
+	for _, arg := range p[2:] {
+		if strings.HasPrefix(arg, "operationId=") {
+			link.OperationID = arg[len("operationId="):]
+		} else if strings.HasPrefix(arg, "params=") {
+			link.Parameters = make(map[string]string)
+			for _, kv := range strings.Split(arg[len("params="):], ",") {
+				pair := strings.SplitN(kv, ":", 2)
+				if len(pair) != 2 {
+					beeLogger.Log.Warnf("[%s.%s] Invalid @Link param: %s", c.controllerName, c.funcName, kv)
+					continue
+				}
+				link.Parameters[pair[0]] = pair[1]
+			}
+		} else {
+			link.Description = arg
+		}
+	}
+	if link.OperationID == "" {
+		return fmt.Errorf("[%s.%s] @Link %s should have an operationId", c.controllerName, c.funcName, p[1])
+	}
+	if _, ok := c.links[p[0]]; !ok {
+		c.links[p[0]] = make(map[string]swagger.Link)
+	}
+	c.links[p[0]][p[1]] = link
+	return nil
+}
+
+// header parses @Header, a header of a response overriding the one declared in router.go
+// @Header 200 X-Rate-Limit int "requests left"
+func (c *operationComments) header(value string) error {
+	p := getparams(value)
+	if len(p) < 2 {
+		return fmt.Errorf("[%s.%s] @Header should have at least a response code and a name", c.controllerName, c.funcName)
+	}
+	name, header, err := parseHeader(p[1:])
+	if err != nil {
+		return err
+	}
+	if _, ok := c.headers[p[0]]; !ok {
+		c.headers[p[0]] = make(map[string]swagger.Header)
+	}
+	c.headers[p[0]][name] = header
+	return nil
+}
+
+// parseHeader returns the header declared by the name, type and description params, the type being string by default
+func parseHeader(p []string) (string, swagger.Header, error) {
+	if len(p) == 0 {
+		return "", swagger.Header{}, errors.New("no name for the header")
+	}
+	header := swagger.Header{Type: "string"}
+	if len(p) > 1 {
+		if t, ok := basicTypes[p[1]]; ok {
+			typeFormat := strings.Split(t, ":")
+			header.Type, header.Format = typeFormat[0], typeFormat[1]
+		} else {
+			beeLogger.Log.Warnf("Unknown type of header %s: %s", p[0], p[1])
+		}
+	}
+	if len(p) > 2 {
+		header.Description = p[2]
+	}
+	return p[0], header, nil
+}
+
+// part parses @Part, a part of a multipart/mixed body, whose schema is binary by default
+// @Part metadata application/json models.UploadMeta "description"
+// @Part file application/octet-stream
+func (c *operationComments) part(value string) error {
+	p := getparams(value)
+	if len(p) < 2 {
+		return fmt.Errorf("[%s.%s] @Part should have at least a name and a content type", c.controllerName, c.funcName)
+	}
+	part := swagger.Part{
+		Name:        p[0],
+		ContentType: p[1],
+		Schema: &swagger.Schema{
+			Type:   "string",
+			Format: "binary",
+		},
+	}
+	if len(p) > 2 && p[2] != "file" {
+		part.Schema = responseSchema(c.fl, c.pkgpath, c.controllerName, p[2], false)
+	}
+	if len(p) > 3 {
+		part.Description = p[3]
+	}
+	c.opts.Parts = append(c.opts.Parts, part)
+	return nil
+}
+
+// callback parses @Callback, a request sent by the API to the client
+// @Callback onEvent {$request.body#/callbackUrl} post {object} models.Event "description"
+func (c *operationComments) callback(value string) error {
+	p := getparams(value)
+	if len(p) < 5 {
+		return fmt.Errorf("[%s.%s] @Callback should have a name, an expression, a method and a body", c.controllerName, c.funcName)
+	}
+	if !httpMethods[strings.ToUpper(p[2])] {
+		return fmt.Errorf("[%s.%s] Invalid @Callback method: %s", c.controllerName, c.funcName, p[2])
+	}
+	if p[3] != "{object}" && p[3] != "{array}" {
+		return fmt.Errorf("[%s.%s] The @Callback body should be an {object} or an {array}", c.controllerName, c.funcName)
+	}
+	callback := swagger.Callback{
+		Name:       p[0],
+		Expression: p[1],
+		Method:     strings.ToLower(p[2]),
+		Schema:     responseSchema(c.fl, c.pkgpath, c.controllerName, p[4], p[3] == "{array}"),
+	}
+	if len(p) > 5 {
+		callback.Description = p[5]
+	}
+	c.opts.Callbacks = append(c.opts.Callbacks, callback)
+	return nil
+}
+
+// server parses @Server, a host serving the operation instead of the API one
+// @Server https://cdn.example.com/v1 "uploads"
+func (c *operationComments) server(value string) error {
+	p := getparams(value)
+	if len(p) < 1 {
+		return fmt.Errorf("[%s.%s] @Server should have a URL", c.controllerName, c.funcName)
+	}
+	server := swagger.Server{URL: p[0]}
+	if u, err := url.Parse(p[0]); err != nil || (u.IsAbs() && u.Host == "") {
+		beeLogger.Log.Warnf("[%s.%s] @Server is not a valid URL: %s", c.controllerName, c.funcName, p[0])
+	}
+	if len(p) > 1 {
+		server.Description = p[1]
+	}
+	c.opts.Servers = append(c.opts.Servers, server)
+	return nil
+}
+
+// security parses @Security
+func (c *operationComments) security(value string) error {
+	if len(c.opts.Security) == 0 {
+		c.opts.Security = make([]map[string][]string, 0)
+	}
+	security, err := getSecurity(value)
+	if err != nil {
+		return fmt.Errorf("[%s.%s] %s", c.controllerName, c.funcName, err)
+	}
+	c.opts.Security = append(c.opts.Security, security)
+	return nil
+}
+
 // parse the func comments
 func parserComments(fl *ast.File, f *ast.FuncDecl, controllerName, pkgpath string) error {
-	var routerPath string
-	var HTTPMethod string
-	opts := swagger.Operation{
-		Responses: make(map[string]swagger.Response),
-	}
 	funcName := f.Name.String()
 	comments := f.Doc
 	funcParamMap := buildParamMap(f.Type.Params)
+	c := &operationComments{
+		fl:             fl,
+		controllerName: controllerName,
+		pkgpath:        pkgpath,
+		funcName:       funcName,
+		opts: swagger.Operation{
+			Responses: make(map[string]swagger.Response),
+		},
+		funcParamMap: funcParamMap,
+		links:        make(map[string]map[string]swagger.Link),
+		headers:      make(map[string]map[string]swagger.Header),
+	}
 
 	if fn := strings.ToUpper(funcName); httpMethods[fn] {
-		HTTPMethod = fn
+		c.httpMethod = fn
 	}
 
 	//TODO: resultMap := buildParamMap(f.Type.Results)
@@ -657,257 +2750,244 @@ func parserComments(fl *ast.File, f *ast.FuncDecl, controllerName, pkgpath strin
 		if len(comments.List) == 0 {
 			return nil
 		}
-		for _, c := range comments.List {
-			t := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
-			if strings.HasPrefix(t, "@router") {
-				elements := strings.TrimSpace(t[len("@router"):])
-				e1 := strings.SplitN(elements, " ", 2)
-				if len(e1) < 1 {
-					return errors.New("you should has router infomation")
-				}
-				routerPath = e1[0]
-				if len(e1) == 2 && e1[1] != "" {
-					e1 = strings.SplitN(e1[1], " ", 2)
-					HTTPMethod = strings.ToUpper(strings.Trim(e1[0], "[]"))
-				} else {
-					HTTPMethod = "GET"
-				}
-			} else if strings.HasPrefix(t, "@Title") {
-				opts.OperationID = controllerName + "." + strings.TrimSpace(t[len("@Title"):])
-			} else if strings.HasPrefix(t, "@Description") {
-				desc := strings.TrimSpace(t[len("@Description"):])
-				opts.Description += fmt.Sprintf("%s\n\n", strings.Trim(desc, "\""))
-			} else if strings.HasPrefix(t, "@Summary") {
-				opts.Summary = strings.TrimSpace(t[len("@Summary"):])
-			} else if strings.HasPrefix(t, "@Success") {
-				ss := strings.TrimSpace(t[len("@Success"):])
-				rs := swagger.Response{}
-				respCode, pos := peekNextSplitString(ss)
-				ss = strings.TrimSpace(ss[pos:])
-				respType, pos := peekNextSplitString(ss)
-				if respType == "{object}" || respType == "{array}" {
-					isArray := respType == "{array}"
-					ss = strings.TrimSpace(ss[pos:])
-					schemaName, pos := peekNextSplitString(ss)
-					if schemaName == "" {
-						beeLogger.Log.Fatalf("[%s.%s] Schema must follow {object} or {array}", controllerName, funcName)
-					}
-					if strings.HasPrefix(schemaName, "[]") {
-						schemaName = schemaName[2:]
-						isArray = true
-					}
-					schema := swagger.Schema{}
-					if sType, ok := basicTypes[schemaName]; ok {
-						typeFormat := strings.Split(sType, ":")
-						schema.Type = typeFormat[0]
-						schema.Format = typeFormat[1]
-					} else {
-						m, mod, realTypes := getModel(fl, schemaName)
-						schema.Ref = "#/definitions/" + m
-						if _, ok := modelsList[pkgpath+controllerName]; !ok {
-							modelsList[pkgpath+controllerName] = make(map[string]swagger.Schema)
-						}
-						modelsList[pkgpath+controllerName][schemaName] = mod
-						appendModels(fl, pkgpath, controllerName, realTypes)
-					}
-					if isArray {
-						rs.Schema = &swagger.Schema{
-							Type:  astTypeArray,
-							Items: &schema,
-						}
-					} else {
-						rs.Schema = &schema
-					}
-					rs.Description = strings.TrimSpace(ss[pos:])
-				} else {
-					rs.Description = strings.TrimSpace(ss)
-				}
-				opts.Responses[respCode] = rs
-			} else if strings.HasPrefix(t, "@Param") {
-				para := swagger.Parameter{}
-				p := getparams(strings.TrimSpace(t[len("@Param "):]))
-				if len(p) < 4 {
-					beeLogger.Log.Fatal(controllerName + "_" + funcName + "'s comments @Param should have at least 4 params")
-				}
-				paramNames := strings.SplitN(p[0], "=>", 2)
-				para.Name = paramNames[0]
-				funcParamName := para.Name
-				if len(paramNames) > 1 {
-					funcParamName = paramNames[1]
-				}
-				paramType, ok := funcParamMap[funcParamName]
-				if ok {
-					delete(funcParamMap, funcParamName)
-				}
+		for _, comment := range comments.List {
+			t := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			if err := c.parse(safeText(t)); err != nil {
+				return err
+			}
+		}
+	} else {
+		return nil
+	}
+	if !c.hasDeprecated && deprecatedControllers[pkgpath+controllerName] {
+		c.opts.Deprecated = true
+	}
+	opts, routes, links := c.opts, c.routes, c.links
+	if len(routes) == 0 && c.httpMethod != "" {
+		routes = []route{{methods: c.httpMethod}}
+	}
 
-				switch p[1] {
-				case "query":
-					fallthrough
-				case "header":
-					fallthrough
-				case "path":
-					fallthrough
-				case "formData":
-					fallthrough
-				case "body":
-					break
-				default:
-					beeLogger.Log.Warnf("[%s.%s] Unknown param location: %s. Possible values are `query`, `header`, `path`, `formData` or `body`.\n", controllerName, funcName, p[1])
-				}
-				para.In = p[1]
-				pp := strings.Split(p[2], ".")
-				typ := pp[len(pp)-1]
-				if len(pp) >= 2 {
-					isArray := false
-					if p[1] == "body" && strings.HasPrefix(p[2], "[]") {
-						p[2] = p[2][2:]
-						isArray = true
-					}
-					m, mod, realTypes := getModel(fl, p[2])
-					if isArray {
-						para.Schema = &swagger.Schema{
-							Type: astTypeArray,
-							Items: &swagger.Schema{
-								Ref: "#/definitions/" + m,
-							},
-						}
-					} else {
-						para.Schema = &swagger.Schema{
-							Ref: "#/definitions/" + m,
-						}
-					}
+	for code, l := range links {
+		rs, ok := opts.Responses[code]
+		if !ok {
+			beeLogger.Log.Warnf("[%s.%s] @Link refers to the undocumented response %s", controllerName, funcName, code)
+			continue
+		}
+		rs.Links = l
+		opts.Responses[code] = rs
+	}
 
-					if _, ok := modelsList[pkgpath+controllerName]; !ok {
-						modelsList[pkgpath+controllerName] = make(map[string]swagger.Schema)
-					}
-					modelsList[pkgpath+controllerName][typ] = mod
-					appendModels(fl, pkgpath, controllerName, realTypes)
-				} else {
-					if typ == "auto" {
-						typ = paramType
-					}
-					setParamType(&para, typ, fl, pkgpath, controllerName)
-				}
-				para.Required, _ = strconv.ParseBool(p[3])
-				para.AllowEmptyValue = !para.Required
-				paramDesc := strings.Trim(p[4], `" `)
-				lines := strings.Split(paramDesc, `\n`)
-				for _, line := range lines {
-					para.Description = fmt.Sprintf("%s\n%s", para.Description, line)
-				}
+	for code := range c.headers {
+		if _, ok := opts.Responses[code]; !ok {
+			beeLogger.Log.Warnf("[%s.%s] @Header refers to the undocumented response %s", controllerName, funcName, code)
+		}
+	}
+	for code, rs := range opts.Responses {
+		// the headers of router.go are sent with every response
+		headers := make(map[string]swagger.Header)
+		for name, header := range responseHeaders {
+			headers[name] = header
+		}
+		for name, header := range c.headers[code] {
+			headers[name] = header
+		}
+		if len(headers) > 0 {
+			rs.Headers = headers
+			opts.Responses[code] = rs
+		}
+	}
 
-				if len(p) >= 6 {
-					para.Default = str2RealType(p[5], para.Type)
-				}
+	if len(routes) > 0 {
+		if opts.OperationID == "" {
+			// without @Title the operation is named after its method
+			opts.OperationID = controllerName + "." + funcName
+		}
+		if c.explicitID != "" {
+			opts.OperationID = c.explicitID
+		}
+		method := pkgpath + ":" + controllerName + "." + funcName
+		if other, ok := operationIDs[opts.OperationID]; ok && other != method {
+			beeLogger.Log.Warnf("[%s.%s] operationId %s is already used by %s", controllerName, funcName, opts.OperationID, other)
+		} else {
+			operationIDs[opts.OperationID] = method
+		}
 
-				if len(p) >= 7 {
-					values := strings.Split(p[6], ":")
-					if len(values) > 0 {
-						para.Enum = make([]interface{}, 0, len(values))
-					}
-					for _, value := range values {
-						para.Enum = append(para.Enum, value)
-					}
-				}
+		// the function parameters which were not mapped follow the documented params, in the path or the query
+		documented := len(opts.Parameters)
+		if hasContentType(opts.Consumes, ajsonPatch) && !hasRequestBody(opts.Parameters) {
+			opts.Parameters = append(opts.Parameters, jsonPatchParam())
+		}
+		for _, para := range opts.Parameters {
+			// formData params can only be sent with a form media type
+			if para.In == "formData" && !hasContentType(opts.Consumes, aform) && !hasContentType(opts.Consumes, aurl) {
+				opts.Consumes = append(opts.Consumes, aform)
+			}
+		}
+		if len(opts.Parts) > 0 && !hasContentType(opts.Consumes, amixed) {
+			opts.Consumes = append(opts.Consumes, amixed)
+		}
+		if _, ok := opts.Extensions["x-max-body-size"]; ok && !hasRequestBody(opts.Parameters) && len(opts.Parts) == 0 {
+			beeLogger.Log.Warnf("[%s.%s] @MaxBodySize is documented for an operation without any body or formData param", controllerName, funcName)
+		}
+		if len(opts.Consumes) > 0 && !hasRequestBody(opts.Parameters) && len(opts.Parts) == 0 {
+			// the media types of @Accept which are produced too, such as json, still document the responses
+			for _, ct := range opts.Consumes {
+				beeLogger.Log.Warnf("[%s.%s] @Accept %s has no effect on the request as there is no body or formData param", controllerName, funcName, ct)
+			}
+			opts.Consumes = nil
+		}
 
-				opts.Parameters = append(opts.Parameters, para)
-			} else if strings.HasPrefix(t, "@Failure") {
-				rs := swagger.Response{}
-				st := strings.TrimSpace(t[len("@Failure"):])
-				var cd []rune
-				var start bool
-				for i, s := range st {
-					if unicode.IsSpace(s) {
-						if start {
-							rs.Description = strings.TrimSpace(st[i+1:])
-							break
-						} else {
-							continue
-						}
-					}
-					start = true
-					cd = append(cd, s)
-				}
-				opts.Responses[string(cd)] = rs
-			} else if strings.HasPrefix(t, "@Deprecated") {
-				opts.Deprecated, _ = strconv.ParseBool(strings.TrimSpace(t[len("@Deprecated"):]))
-			} else if strings.HasPrefix(t, "@Accept") {
-				accepts := strings.Split(strings.TrimSpace(strings.TrimSpace(t[len("@Accept"):])), ",")
-				for _, a := range accepts {
-					switch a {
-					case "json":
-						opts.Consumes = append(opts.Consumes, ajson)
-						opts.Produces = append(opts.Produces, ajson)
-					case "xml":
-						opts.Consumes = append(opts.Consumes, axml)
-						opts.Produces = append(opts.Produces, axml)
-					case "plain":
-						opts.Consumes = append(opts.Consumes, aplain)
-						opts.Produces = append(opts.Produces, aplain)
-					case "html":
-						opts.Consumes = append(opts.Consumes, ahtml)
-						opts.Produces = append(opts.Produces, ahtml)
-					case "form":
-						opts.Consumes = append(opts.Consumes, aform)
-					}
+		for i, rt := range routes {
+			op := opts
+			if i > 0 {
+				// the operations of the other paths need ids of their own
+				op.OperationID = fmt.Sprintf("%s_%d", opts.OperationID, i+1)
+				if other, ok := operationIDs[op.OperationID]; ok && other != method {
+					beeLogger.Log.Warnf("[%s.%s] operationId %s is already used by %s", controllerName, funcName, op.OperationID, other)
+				} else {
+					operationIDs[op.OperationID] = method
 				}
-			} else if strings.HasPrefix(t, "@Security") {
-				if len(opts.Security) == 0 {
-					opts.Security = make([]map[string][]string, 0)
+			}
+			op.Parameters = append([]swagger.Parameter(nil), opts.Parameters[:documented]...)
+			for name, typ := range funcParamMap {
+				para := swagger.Parameter{}
+				para.Name = name
+				setParamType(&para, typ, fl, pkgpath, controllerName)
+				if paramInPath(name, rt.path) {
+					para.In = "path"
+				} else {
+					para.In = "query"
 				}
-				opts.Security = append(opts.Security, getSecurity(t))
+				op.Parameters = append(op.Parameters, para)
 			}
+			op.Parameters = append(op.Parameters, opts.Parameters[documented:]...)
+			addOperation(pkgpath+controllerName, rt, &op)
+		}
+	}
+	return nil
+}
+
+// addOperation documents op as the operation of the controller serving the path and methods of rt
+func addOperation(controllerName string, rt route, op *swagger.Operation) {
+	var item *swagger.Item
+	if itemList, ok := controllerList[controllerName]; ok {
+		if it, ok := itemList[rt.path]; !ok {
+			item = &swagger.Item{}
+		} else {
+			item = it
 		}
 	} else {
-		return nil
+		controllerList[controllerName] = make(map[string]*swagger.Item)
+		item = &swagger.Item{}
 	}
+	for _, hm := range strings.Split(rt.methods, ",") {
+		switch hm {
+		case "GET":
+			item.Get = op
+		case "POST":
+			item.Post = op
+		case "PUT":
+			item.Put = op
+		case "PATCH":
+			item.Patch = op
+		case "DELETE":
+			item.Delete = op
+		case "HEAD":
+			item.Head = op
+		case "OPTIONS":
+			item.Options = op
+		}
+	}
+	controllerList[controllerName][rt.path] = item
+}
 
-	if HTTPMethod != "" {
-		//Go over function parameters which were not mapped and create swagger params for them
-		for name, typ := range funcParamMap {
-			para := swagger.Parameter{}
-			para.Name = name
-			setParamType(&para, typ, fl, pkgpath, controllerName)
-			if paramInPath(name, routerPath) {
-				para.In = "path"
-			} else {
-				para.In = "query"
+// responseSchema builds the schema of a response returning schemaName, registering its models
+func responseSchema(fl *ast.File, pkgpath, controllerName, schemaName string, isArray bool) *swagger.Schema {
+	if strings.HasPrefix(schemaName, "[]") {
+		schemaName = schemaName[2:]
+		isArray = true
+	}
+	schema := swagger.Schema{}
+	if value, ok := mapValueType(schemaName); ok {
+		// the values of a map, such as map[string]models.Status, are documented by their own schema
+		schema.Type = astTypeObject
+		schema.AdditionalProperties = responseSchema(fl, pkgpath, controllerName, value, false)
+	} else if sType, ok := basicTypes[schemaName]; ok {
+		typeFormat := strings.Split(sType, ":")
+		schema.Type = typeFormat[0]
+		schema.Format = typeFormat[1]
+	} else if _, ok := modelsList[pkgpath+controllerName][schemaName]; ok && hasDefinition(schemaName) {
+		// already registered by another response of the controller
+		schema.Ref = "#/definitions/" + schemaName
+	} else {
+		m, mod, realTypes := getModel(fl, schemaName)
+		schema.Ref = "#/definitions/" + m
+		if _, ok := modelsList[pkgpath+controllerName]; !ok {
+			modelsList[pkgpath+controllerName] = make(map[string]swagger.Schema)
+		}
+		modelsList[pkgpath+controllerName][schemaName] = mod
+		appendModels(fl, pkgpath, controllerName, realTypes)
+	}
+	if def, ok := rootapi.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]; ok && def.Type == astTypeArray && def.Items != nil {
+		// a named slice type, such as type UserList []User, is documented as the array itself
+		items := *def.Items
+		schema = swagger.Schema{
+			Type:  astTypeArray,
+			Items: &items,
+		}
+	}
+	if isArray {
+		return &swagger.Schema{
+			Type:  astTypeArray,
+			Items: &schema,
+		}
+	}
+	return &schema
+}
+
+// mapValueType returns the value type of a map type name, e.g. models.Status for map[string]models.Status
+func mapValueType(name string) (string, bool) {
+	if !strings.HasPrefix(name, "map[") {
+		return "", false
+	}
+	depth := 0
+	for i, r := range name[len("map"):] {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			if depth--; depth == 0 {
+				value := name[len("map")+i+1:]
+				return value, value != ""
 			}
-			opts.Parameters = append(opts.Parameters, para)
 		}
+	}
+	return "", false
+}
 
-		var item *swagger.Item
-		if itemList, ok := controllerList[pkgpath+controllerName]; ok {
-			if it, ok := itemList[routerPath]; !ok {
-				item = &swagger.Item{}
-			} else {
-				item = it
+func hasDefinition(name string) bool {
+	_, ok := rootapi.Definitions[name]
+	return ok
+}
+
+// sanitizeOperationID turns a title into an identifier usable as operationId:
+// words are joined in camelCase and characters other than letters, digits and '_' are dropped.
+func sanitizeOperationID(title string) string {
+	var id []rune
+	upper := false
+	for _, r := range title {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			if upper && len(id) > 0 {
+				r = unicode.ToUpper(r)
 			}
-		} else {
-			controllerList[pkgpath+controllerName] = make(map[string]*swagger.Item)
-			item = &swagger.Item{}
+			id = append(id, r)
+			upper = false
+		default:
+			upper = true
 		}
-		for _, hm := range strings.Split(HTTPMethod, ",") {
-			switch hm {
-			case "GET":
-				item.Get = &opts
-			case "POST":
-				item.Post = &opts
-			case "PUT":
-				item.Put = &opts
-			case "PATCH":
-				item.Patch = &opts
-			case "DELETE":
-				item.Delete = &opts
-			case "HEAD":
-				item.Head = &opts
-			case "OPTIONS":
-				item.Options = &opts
-			}
-		}
-		controllerList[pkgpath+controllerName][routerPath] = item
 	}
-	return nil
+	return string(id)
 }
 
 func setParamType(para *swagger.Parameter, typ string, fl *ast.File, pkgpath, controllerName string) {
@@ -919,6 +2999,18 @@ func setParamType(para *swagger.Parameter, typ string, fl *ast.File, pkgpath, co
 		typ = typ[2:]
 		isArray = true
 	}
+	if typ == astTypeObject && !isArray {
+		// free-form JSON, without any model
+		if para.In != "body" {
+			beeLogger.Log.Warnf("[%s] Only body params may be objects, %s is documented as a string", controllerName, para.Name)
+			para.Type = "string"
+			return
+		}
+		para.Schema = &swagger.Schema{
+			Type: astTypeObject,
+		}
+		return
+	}
 	if typ == "string" || typ == "number" || typ == "integer" || typ == "boolean" ||
 		typ == astTypeArray || typ == "file" {
 		paraType = typ
@@ -953,6 +3045,12 @@ func setParamType(para *swagger.Parameter, typ string, fl *ast.File, pkgpath, co
 				Format: paraFormat,
 			}
 		}
+	} else if para.In == "body" && paraType == "file" {
+		// a raw binary upload, which is not multipart encoded
+		para.Schema = &swagger.Schema{
+			Type:   "string",
+			Format: "binary",
+		}
 	} else {
 		para.Type = paraType
 		para.Format = paraFormat
@@ -960,9 +3058,111 @@ func setParamType(para *swagger.Parameter, typ string, fl *ast.File, pkgpath, co
 
 }
 
+// queryParams flattens the fields of a struct model into query params, the fields of
+// the embedded structs included
+func queryParams(fl *ast.File, m swagger.Schema, seen map[string]bool) (params []swagger.Parameter) {
+	for _, s := range m.AllOf {
+		if s != nil && s.Ref == "" {
+			params = append(params, queryParams(fl, *s, seen)...)
+		}
+	}
+	for _, s := range m.AllOf {
+		if s != nil && s.Ref != "" {
+			_, embedded, _ := getModel(fl, strings.TrimPrefix(s.Ref, "#/definitions/"))
+			params = append(params, queryParams(fl, embedded, seen)...)
+		}
+	}
+
+	names := make([]string, 0, len(m.Properties))
+	for name := range m.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		prop := m.Properties[name]
+		para := swagger.Parameter{
+			In:          "query",
+			Name:        name,
+			Description: prop.Description,
+			Type:        prop.Type,
+			Format:      prop.Format,
+			Default:     prop.Default,
+		}
+		for _, r := range m.Required {
+			para.Required = para.Required || r == name
+		}
+		para.AllowEmptyValue = !para.Required
+		if prop.Type == astTypeArray && prop.Items != nil && prop.Items.Ref == "" && isScalarType(prop.Items.Type) {
+			para.Items = &swagger.ParameterItems{
+				Type:   prop.Items.Type,
+				Format: prop.Items.Format,
+			}
+		} else if !isScalarType(prop.Type) {
+			beeLogger.Log.Warnf("Cannot document the field %s of %s as a query param, only scalars and arrays of scalars are supported", name, m.Title)
+			continue
+		}
+		params = append(params, para)
+	}
+	return
+}
+
+// jsonPatchParam returns the body param of a JSON Patch request, the list of the operations
+// applied to the resource, and registers the definition of these operations
+func jsonPatchParam() swagger.Parameter {
+	if len(rootapi.Definitions) == 0 {
+		rootapi.Definitions = make(map[string]swagger.Schema)
+	}
+	rootapi.Definitions["JSONPatchOperation"] = swagger.Schema{
+		Title:    "JSONPatchOperation",
+		Type:     astTypeObject,
+		Required: []string{"op", "path"},
+		Properties: map[string]swagger.Propertie{
+			"op": {
+				Type: "string",
+				Enum: []interface{}{"add", "remove", "replace", "move", "copy", "test"},
+			},
+			"path": {
+				Type:        "string",
+				Description: "JSON Pointer to the target location",
+			},
+			"from": {
+				Type:        "string",
+				Description: "JSON Pointer to the source location of move and copy",
+			},
+			"value": {
+				Description: "value of add, replace and test",
+			},
+		},
+	}
+	return swagger.Parameter{
+		In:          "body",
+		Name:        "body",
+		Description: "JSON Patch operations",
+		Required:    true,
+		Schema: &swagger.Schema{
+			Type: astTypeArray,
+			Items: &swagger.Schema{
+				Ref: "#/definitions/JSONPatchOperation",
+			},
+		},
+	}
+}
+
+func hasRequestBody(params []swagger.Parameter) bool {
+	for _, p := range params {
+		if p.In == "body" || p.In == "formData" {
+			return true
+		}
+	}
+	return false
+}
+
 func paramInPath(name, route string) bool {
-	return strings.HasSuffix(route, ":"+name) ||
-		strings.Contains(route, ":"+name+"/")
+	return strings.Contains(urlReplace(route), "{"+name+"}")
 }
 
 func getFunctionParamType(t ast.Expr) string {
@@ -1005,17 +3205,166 @@ func buildParamMap(list *ast.FieldList) map[string]string {
 	return result
 }
 
+// paramOptionKeys lists the options which may trail the fields of a @Param as key=value or key:value
+var paramOptionKeys = map[string]bool{
+	"collectionFormat": true,
+	"example":          true,
+	"allowEmptyValue":  true,
+}
+
+// paramOptions separates the trailing key=value options of a @Param from its positional fields
+// @Param	ids	header	[]string	false	"ids"	collectionFormat=pipes
+// @Param	limit	query	int	false	"limit"	10	example=50
+// @Param	body	body	models.User	true	"user"	example:file:create.json
+// @Param	q	query	string	false	"search"	allowEmptyValue=false
+func paramOptions(p []string) (fields []string, opts map[string]string) {
+	opts = make(map[string]string)
+	for i, f := range p {
+		if i > 4 {
+			if j := strings.IndexAny(f, "=:"); j > 0 && paramOptionKeys[f[:j]] {
+				opts[f[:j]] = f[j+1:]
+				continue
+			}
+		}
+		fields = append(fields, f)
+	}
+	return
+}
+
+// exampleMismatches loosely checks an example against a schema, reporting the obvious
+// mismatches: a wrong shape, missing required properties or unknown ones
+func exampleMismatches(schema swagger.Schema, value interface{}, at string) (mismatches []string) {
+	if schema.Ref != "" {
+		def, ok := rootapi.Definitions[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+		if !ok {
+			return nil
+		}
+		schema = def
+	}
+	required := schema.Required
+	properties := schema.Properties
+	for _, part := range schema.AllOf {
+		if part == nil {
+			continue
+		}
+		if part.Ref != "" {
+			def, ok := rootapi.Definitions[strings.TrimPrefix(part.Ref, "#/definitions/")]
+			if !ok {
+				return nil
+			}
+			part = &def
+		}
+		required = append(required, part.Required...)
+		for name, prop := range part.Properties {
+			if properties == nil {
+				properties = make(map[string]swagger.Propertie)
+			}
+			properties[name] = prop
+		}
+	}
+	if at == "" {
+		at = "the example"
+	}
+	switch {
+	case schema.Type == astTypeArray:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{at + " should be an array"}
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				mismatches = append(mismatches, exampleMismatches(*schema.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+			}
+		}
+	case schema.AdditionalProperties != nil:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{at + " should be an object"}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			mismatches = append(mismatches, exampleMismatches(*schema.AdditionalProperties, object[name], at+"."+name)...)
+		}
+	case len(properties) > 0:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return []string{at + " should be an object"}
+		}
+		for _, name := range required {
+			if _, ok := object[name]; !ok {
+				mismatches = append(mismatches, fmt.Sprintf("%s misses the required property %s", at, name))
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, ok := properties[name]; !ok {
+				mismatches = append(mismatches, fmt.Sprintf("%s has the unknown property %s", at, name))
+			}
+		}
+	}
+	return
+}
+
+// inlineSchema builds the object schema declared inline by a @Param, e.g.
+// {name:string required,age:int} where required fields are marked as such
+func inlineSchema(decl string) *swagger.Schema {
+	schema := &swagger.Schema{
+		Type:       astTypeObject,
+		Properties: make(map[string]swagger.Propertie),
+	}
+	for _, f := range strings.Split(strings.Trim(decl, "{}"), ",") {
+		fields := strings.Fields(f)
+		if len(fields) == 0 {
+			continue
+		}
+		nameType := strings.SplitN(fields[0], ":", 2)
+		mp := swagger.Propertie{Type: "string"}
+		if len(nameType) == 2 {
+			if sType, ok := basicTypes[nameType[1]]; ok {
+				typeFormat := strings.Split(sType, ":")
+				mp.Type = typeFormat[0]
+				mp.Format = typeFormat[1]
+			} else if isScalarType(nameType[1]) || nameType[1] == astTypeObject {
+				mp.Type = nameType[1]
+			} else {
+				beeLogger.Log.Warnf("Unknown type of inline field %s: %s", nameType[0], nameType[1])
+			}
+		}
+		schema.Properties[nameType[0]] = mp
+		if len(fields) > 1 && fields[1] == "required" {
+			schema.Required = append(schema.Required, nameType[0])
+		}
+	}
+	return schema
+}
+
 // analisys params return []string
 // @Param	query		form	 string	true		"The email for login"
 // [query form string true "The email for login"]
+// params are separated by any run of spaces and tabs, mixed or not. Between quotes the spaces
+// and tabs belong to the param, as do the spaces between braces so that inline schemas stay whole
 func getparams(str string) []string {
 	var s []rune
 	var j int
 	var start bool
 	var r []string
 	var quoted int8
+	var braces int
 	for _, c := range str {
-		if unicode.IsSpace(c) && quoted == 0 {
+		if c == '{' && quoted == 0 {
+			braces++
+		} else if c == '}' && quoted == 0 && braces > 0 {
+			braces--
+		}
+		if unicode.IsSpace(c) && quoted == 0 && braces == 0 {
 			if !start {
 				continue
 			} else {
@@ -1028,7 +3377,8 @@ func getparams(str string) []string {
 		}
 
 		start = true
-		if c == '"' {
+		// quotes inside braces belong to the value, e.g. a JSON example
+		if c == '"' && braces == 0 {
 			quoted ^= 1
 			continue
 		}
@@ -1041,13 +3391,22 @@ func getparams(str string) []string {
 }
 
 func getModel(fl *ast.File, str string) (definitionName string, m swagger.Schema, realTypes []string) {
-	strs := strings.Split(str, ".")
+	typeArgs := ""
+	if i := strings.Index(str, "["); i > 0 {
+		// an instantiated generic type such as models.Response[[]models.User]
+		if expr, err := parser.ParseExpr(str); err == nil {
+			str = typeString(qualifyType(fl.Name.Name, expr))
+			i = strings.Index(str, "[")
+		}
+		typeArgs = str[i:]
+	}
+	strs := strings.Split(strings.TrimSuffix(str, typeArgs), ".")
 	// strs = [packageName].[objectName]
 	packageName := strs[0]
 	if len(strs) == 1 {
 		packageName = fl.Name.Name
 	}
-	objectname := strs[len(strs)-1]
+	objectname := strs[len(strs)-1] + typeArgs
 
 	// Default all swagger schemas to object, if no other type is found
 	m.Type = astTypeObject
@@ -1059,24 +3418,9 @@ func getModel(fl *ast.File, str string) (definitionName string, m swagger.Schema
 		copy(localPkgs, astPkgs)
 		parsePackageFromFile(&localPkgs, fl)
 
-	L:
-		for _, pkg := range localPkgs {
-			if packageName == pkg.Name {
-				for _, fl := range pkg.Files {
-					for k, d := range fl.Scope.Objects {
-						if d.Kind == ast.Typ {
-							if k != objectname {
-								// Still searching for the right object
-								continue
-							}
-							parseObject(d, k, &m, &realTypes, fl, localPkgs, packageName)
-
-							// When we've found the correct object, we can stop searching
-							break L
-						}
-					}
-				}
-			}
+		// Types of the package win over the ones of its external test package, only used for examples
+		if !findModel(localPkgs, packageName, objectname, &m, &realTypes) {
+			findModel(localPkgs, packageName+"_test", objectname, &m, &realTypes)
 		}
 	}
 
@@ -1091,41 +3435,412 @@ func getModel(fl *ast.File, str string) (definitionName string, m swagger.Schema
 	if len(rootapi.Definitions) == 0 {
 		rootapi.Definitions = make(map[string]swagger.Schema)
 	}
-	rootapi.Definitions[str] = m
-	return str, m, realTypes
+	rootapi.Definitions[str] = m
+	return str, m, realTypes
+}
+
+// findModel parses the type objectname of the package packageName into m, reporting whether it was found.
+// The type arguments of an instantiated generic type, e.g. Response[[]models.User], replace its type parameters.
+func findModel(astPkgs []*ast.Package, packageName, objectname string, m *swagger.Schema, realTypes *[]string) bool {
+	name, typeArgs := objectname, []ast.Expr(nil)
+	if i := strings.Index(objectname, "["); i > 0 {
+		name = objectname[:i]
+		expr, err := parser.ParseExpr(objectname)
+		if err != nil {
+			beeLogger.Log.Warnf("Cannot parse the generic type %s.%s: %s", packageName, objectname, err)
+			return false
+		}
+		switch t := expr.(type) {
+		case *ast.IndexExpr:
+			typeArgs = []ast.Expr{t.Index}
+		case *ast.IndexListExpr:
+			typeArgs = t.Indices
+		}
+	}
+	for _, pkg := range astPkgs {
+		if packageName != pkg.Name {
+			continue
+		}
+		for _, fl := range pkg.Files {
+			if d, ok := fl.Scope.Objects[name]; ok && d.Kind == ast.Typ {
+				if ts, ok := d.Decl.(*ast.TypeSpec); ok && strings.Contains(typeDoc(fl, ts), "@EmptyModel") {
+					emptyModels[packageName+"."+objectname] = true
+				}
+				if typeArgs != nil {
+					if d = instantiate(d, typeArgs); d == nil {
+						beeLogger.Log.Warnf("Wrong number of type arguments for %s.%s", packageName, objectname)
+						return false
+					}
+				}
+				parseObject(d, objectname, m, realTypes, fl, astPkgs, packageName)
+				if ts, ok := d.Decl.(*ast.TypeSpec); ok {
+					if ref := annotationValue(typeDoc(fl, ts), "@Enum"); ref != "" {
+						setLiteralEnum(m, astPkgs, packageName, ref)
+					}
+				}
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// instantiate returns the generic type d whose type parameters are replaced by typeArgs,
+// or nil when their numbers differ
+func instantiate(d *ast.Object, typeArgs []ast.Expr) *ast.Object {
+	ts, ok := d.Decl.(*ast.TypeSpec)
+	if !ok || ts.TypeParams == nil {
+		return nil
+	}
+	params := make(map[string]ast.Expr)
+	for _, field := range ts.TypeParams.List {
+		for _, name := range field.Names {
+			if len(params) == len(typeArgs) {
+				return nil
+			}
+			params[name.Name] = typeArgs[len(params)]
+		}
+	}
+	if len(params) != len(typeArgs) {
+		return nil
+	}
+	return &ast.Object{
+		Kind: d.Kind,
+		Name: d.Name,
+		Decl: &ast.TypeSpec{Doc: ts.Doc, Name: ts.Name, Type: substituteType(ts.Type, params)},
+	}
+}
+
+// substituteType returns a copy of the type expression expr whose type parameters are replaced
+// by the types of params
+func substituteType(expr ast.Expr, params map[string]ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if arg, ok := params[t.Name]; ok {
+			return arg
+		}
+	case *ast.StarExpr:
+		return &ast.StarExpr{Star: t.Star, X: substituteType(t.X, params)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Lbrack: t.Lbrack, Len: t.Len, Elt: substituteType(t.Elt, params)}
+	case *ast.MapType:
+		return &ast.MapType{Map: t.Map, Key: substituteType(t.Key, params), Value: substituteType(t.Value, params)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: t.X, Lbrack: t.Lbrack, Index: substituteType(t.Index, params), Rbrack: t.Rbrack}
+	case *ast.IndexListExpr:
+		indices := make([]ast.Expr, len(t.Indices))
+		for i, index := range t.Indices {
+			indices[i] = substituteType(index, params)
+		}
+		return &ast.IndexListExpr{X: t.X, Lbrack: t.Lbrack, Indices: indices, Rbrack: t.Rbrack}
+	case *ast.StructType:
+		fields := &ast.FieldList{Opening: t.Fields.Opening, Closing: t.Fields.Closing}
+		for _, field := range t.Fields.List {
+			fields.List = append(fields.List, &ast.Field{
+				Doc:     field.Doc,
+				Names:   field.Names,
+				Type:    substituteType(field.Type, params),
+				Tag:     field.Tag,
+				Comment: field.Comment,
+			})
+		}
+		return &ast.StructType{Struct: t.Struct, Fields: fields, Incomplete: t.Incomplete}
+	}
+	return expr
+}
+
+// qualifyType returns a copy of the type expression expr whose named types without package
+// qualifier are qualified by packageName, e.g. Page[Item] becoming models.Page[models.Item]
+func qualifyType(packageName string, expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if !isBasicType(t.Name) && t.Name != "any" {
+			return &ast.SelectorExpr{X: ast.NewIdent(packageName), Sel: t}
+		}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualifyType(packageName, t.X)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: qualifyType(packageName, t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualifyType(packageName, t.Key), Value: qualifyType(packageName, t.Value)}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: qualifyType(packageName, t.X), Index: qualifyType(packageName, t.Index)}
+	case *ast.IndexListExpr:
+		indices := make([]ast.Expr, len(t.Indices))
+		for i, index := range t.Indices {
+			indices[i] = qualifyType(packageName, index)
+		}
+		return &ast.IndexListExpr{X: qualifyType(packageName, t.X), Indices: indices}
+	}
+	return expr
+}
+
+// annotationValue returns the value of the annotation of a doc comment, or "" when it has none
+func annotationValue(doc, annotation string) string {
+	for _, line := range splitLines(doc) {
+		line = strings.TrimSpace(line)
+		if hasAnnotation(line, annotation) {
+			return strings.TrimSpace(line[len(annotation):])
+		}
+	}
+	return ""
+}
+
+// setLiteralEnum sets the enum of m to the keys of the map literal, or the elements of the slice
+// literal, assigned to the package-level variable ref, e.g. @Enum models.AllowedStatuses
+func setLiteralEnum(m *swagger.Schema, astPkgs []*ast.Package, packageName, ref string) {
+	if i := strings.LastIndex(ref, "."); i >= 0 {
+		packageName, ref = ref[:i], ref[i+1:]
+	}
+	for _, pkg := range astPkgs {
+		if pkg.Name != packageName {
+			continue
+		}
+		for _, fl := range pkg.Files {
+			obj, ok := fl.Scope.Objects[ref]
+			if !ok || obj.Kind != ast.Var {
+				continue
+			}
+			vs, ok := obj.Decl.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range vs.Names {
+				if name.Name != ref || i >= len(vs.Values) {
+					continue
+				}
+				lit, ok := vs.Values[i].(*ast.CompositeLit)
+				if !ok {
+					break
+				}
+				var values []interface{}
+				for _, elt := range lit.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						elt = kv.Key
+					}
+					if v, ok := literalValue(astPkgs, packageName, elt); ok {
+						values = append(values, v)
+					} else {
+						beeLogger.Log.Warnf("Unsupported enum value of %s.%s: %s", packageName, ref, typeString(elt))
+					}
+				}
+				if len(values) > 0 {
+					m.Enum = values
+					m.Example = values[0]
+				}
+				return
+			}
+		}
+	}
+	beeLogger.Log.Warnf("Cannot find the map or slice literal of @Enum %s.%s", packageName, ref)
+}
+
+// literalValue returns the value of a basic literal, or of the constant of the package it names
+func literalValue(astPkgs []*ast.Package, packageName string, expr ast.Expr) (interface{}, bool) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		for _, pkg := range astPkgs {
+			if pkg.Name != packageName {
+				continue
+			}
+			for _, fl := range pkg.Files {
+				obj, ok := fl.Scope.Objects[ident.Name]
+				if !ok || obj.Kind != ast.Con {
+					continue
+				}
+				if vs, ok := obj.Decl.(*ast.ValueSpec); ok {
+					for i, name := range vs.Names {
+						if name.Name == ident.Name && i < len(vs.Values) {
+							return literalValue(astPkgs, packageName, vs.Values[i])
+						}
+					}
+				}
+			}
+		}
+		return nil, false
+	}
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return nil, false
+	}
+	switch lit.Kind {
+	case token.INT:
+		if v, err := strconv.ParseInt(lit.Value, 0, 64); err == nil {
+			return v, true
+		}
+	case token.FLOAT:
+		if v, err := strconv.ParseFloat(lit.Value, 64); err == nil {
+			return v, true
+		}
+	case token.STRING, token.CHAR:
+		if v, err := strconv.Unquote(lit.Value); err == nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// typeDoc returns the doc comment of a type, which belongs to its declaration when it is not grouped
+func typeDoc(fl *ast.File, ts *ast.TypeSpec) string {
+	if ts.Doc != nil {
+		return ts.Doc.Text()
+	}
+	for _, decl := range fl.Decls {
+		if gd, ok := decl.(*ast.GenDecl); ok && gd.Doc != nil {
+			for _, spec := range gd.Specs {
+				if spec == ts {
+					return gd.Doc.Text()
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func parseObject(d *ast.Object, k string, m *swagger.Schema, realTypes *[]string, fl *ast.File, astPkgs []*ast.Package, packageName string) {
+	ts, ok := d.Decl.(*ast.TypeSpec)
+	if !ok {
+		beeLogger.Log.Warnf("Unknown type without TypeSpec: %v", d.Name)
+		return
+	}
+	// TODO support other types, such as `MapType`, `InterfaceType` etc...
+	switch t := ts.Type.(type) {
+	case *ast.ArrayType:
+		m.Title = k
+		m.Type = astTypeArray
+		elt := t.Elt
+		if star, ok := elt.(*ast.StarExpr); ok {
+			elt = star.X
+		}
+		if isBasicType(fmt.Sprint(elt)) {
+			typeFormat := strings.Split(basicTypes[fmt.Sprint(elt)], ":")
+			m.Items = &swagger.Schema{
+				Type:   typeFormat[0],
+				Format: typeFormat[1],
+			}
+		} else {
+			objectName := typeString(qualifyType(packageName, elt))
+			if _, ok := rootapi.Definitions[objectName]; !ok {
+				objectName, _, _ = getModel(fl, objectName)
+			}
+			m.Items = &swagger.Schema{
+				Ref: "#/definitions/" + objectName,
+			}
+		}
+	case *ast.Ident:
+		parseIdent(t, k, m, declaringPackage(astPkgs, fl))
+	case *ast.StructType:
+		parseStruct(t, k, m, realTypes, astPkgs, packageName)
+	}
+}
+
+// declaringPackage returns the package of astPkgs holding the file fl
+func declaringPackage(astPkgs []*ast.Package, fl *ast.File) *ast.Package {
+	for _, pkg := range astPkgs {
+		for _, f := range pkg.Files {
+			if f == fl {
+				return pkg
+			}
+		}
+	}
+	return nil
+}
+
+// constValue computes the value of a constant expression made of literals and iota,
+// such as 1 << iota or iota + 1
+func constValue(expr ast.Expr, iota int) (constant.Value, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		v := constant.MakeFromLiteral(e.Value, e.Kind, 0)
+		return v, v.Kind() != constant.Unknown
+	case *ast.Ident:
+		if e.Name == "iota" {
+			return constant.MakeInt64(int64(iota)), true
+		}
+	case *ast.ParenExpr:
+		return constValue(e.X, iota)
+	case *ast.UnaryExpr:
+		x, ok := constValue(e.X, iota)
+		if !ok || !isNumericConst(x) || (e.Op != token.ADD && e.Op != token.SUB && e.Op != token.XOR) {
+			return nil, false
+		}
+		return constant.UnaryOp(e.Op, x, 0), true
+	case *ast.BinaryExpr:
+		x, ok := constValue(e.X, iota)
+		if !ok {
+			return nil, false
+		}
+		y, ok := constValue(e.Y, iota)
+		if !ok {
+			return nil, false
+		}
+		if x.Kind() == constant.String && y.Kind() == constant.String && e.Op == token.ADD {
+			return constant.BinaryOp(x, e.Op, y), true
+		}
+		if !isNumericConst(x) || !isNumericConst(y) {
+			return nil, false
+		}
+		switch e.Op {
+		case token.SHL, token.SHR:
+			s, ok := constant.Uint64Val(y)
+			if !ok || x.Kind() != constant.Int {
+				return nil, false
+			}
+			return constant.Shift(x, e.Op, uint(s)), true
+		case token.ADD, token.SUB, token.MUL, token.AND, token.OR, token.XOR, token.AND_NOT:
+			return constant.BinaryOp(x, e.Op, y), true
+		case token.QUO:
+			if constant.Sign(y) == 0 {
+				return nil, false
+			}
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y), true
+			}
+			return constant.BinaryOp(x, e.Op, y), true
+		case token.REM:
+			if constant.Sign(y) == 0 || x.Kind() != constant.Int || y.Kind() != constant.Int {
+				return nil, false
+			}
+			return constant.BinaryOp(x, e.Op, y), true
+		}
+	}
+	return nil, false
 }
 
-func parseObject(d *ast.Object, k string, m *swagger.Schema, realTypes *[]string, fl *ast.File, astPkgs []*ast.Package, packageName string) {
-	ts, ok := d.Decl.(*ast.TypeSpec)
-	if !ok {
-		beeLogger.Log.Fatalf("Unknown type without TypeSec: %v", d)
+// typedConstValue returns the value of a constant as the JSON value of the swagger type,
+// e.g. a number for the rune 'a' of an integer enum
+func typedConstValue(v constant.Value, swaggerType string) interface{} {
+	switch {
+	case swaggerType == "integer" && isNumericConst(v):
+		if i, ok := constant.Int64Val(constant.ToInt(v)); ok {
+			return int(i)
+		}
+	case swaggerType == "number" && isNumericConst(v):
+		f, _ := constant.Float64Val(constant.ToFloat(v))
+		return f
+	case v.Kind() == constant.String:
+		return constant.StringVal(v)
+	case v.Kind() == constant.Bool:
+		return constant.BoolVal(v)
 	}
-	// TODO support other types, such as `MapType`, `InterfaceType` etc...
-	switch t := ts.Type.(type) {
-	case *ast.ArrayType:
-		m.Title = k
-		m.Type = astTypeArray
-		if isBasicType(fmt.Sprint(t.Elt)) {
-			typeFormat := strings.Split(basicTypes[fmt.Sprint(t.Elt)], ":")
-			m.Format = typeFormat[0]
-		} else {
-			objectName := packageName + "." + fmt.Sprint(t.Elt)
-			if _, ok := rootapi.Definitions[objectName]; !ok {
-				objectName, _, _ = getModel(fl, objectName)
-			}
-			m.Items = &swagger.Schema{
-				Ref: "#/definitions/" + objectName,
-			}
+	if v.Kind() == constant.Int {
+		if i, ok := constant.Int64Val(v); ok {
+			return int(i)
 		}
-	case *ast.Ident:
-		parseIdent(t, k, m, astPkgs)
-	case *ast.StructType:
-		parseStruct(t, k, m, realTypes, astPkgs, packageName)
 	}
+	if v.Kind() == constant.Float {
+		f, _ := constant.Float64Val(v)
+		return f
+	}
+	return v.ExactString()
+}
+
+func isNumericConst(v constant.Value) bool {
+	return v.Kind() == constant.Int || v.Kind() == constant.Float
 }
 
-// parse as enum, in the package, find out all consts with the same type
-func parseIdent(st *ast.Ident, k string, m *swagger.Schema, astPkgs []*ast.Package) {
+// parse as enum, in the package declaring the type, find out all consts with the same type,
+// whichever of its files declare them
+func parseIdent(st *ast.Ident, k string, m *swagger.Schema, pkg *ast.Package) {
 	m.Title = k
 	basicType := fmt.Sprint(st)
 	if object, isStdLibObject := stdlibObject[basicType]; isStdLibObject {
@@ -1138,52 +3853,46 @@ func parseIdent(st *ast.Ident, k string, m *swagger.Schema, astPkgs []*ast.Packa
 	}
 	enums := make(map[int]string)
 	enumValues := make(map[int]interface{})
-	for _, pkg := range astPkgs {
+	if pkg != nil {
 		for _, fl := range pkg.Files {
-			for _, obj := range fl.Scope.Objects {
-				if obj.Kind == ast.Con {
-					vs, ok := obj.Decl.(*ast.ValueSpec)
-					if !ok {
-						beeLogger.Log.Fatalf("Unknown type without ValueSpec: %v", vs)
-					}
-
-					ti, ok := vs.Type.(*ast.Ident)
+			for _, decl := range fl.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.CONST {
+					continue
+				}
+				// a spec without type nor values repeats those of the previous one, iota being its index
+				var typ ast.Expr
+				var values []ast.Expr
+				for iota, spec := range gd.Specs {
+					vs, ok := spec.(*ast.ValueSpec)
 					if !ok {
-						// TODO type inference, iota not support yet
 						continue
 					}
+					if vs.Type != nil || len(vs.Values) > 0 {
+						typ, values = vs.Type, vs.Values
+					}
 					// Only add the enums that are defined by the current identifier
-					if ti.Name != k {
+					if ti, ok := typ.(*ast.Ident); !ok || ti.Name != k {
 						continue
 					}
 
 					// For all names and values, aggregate them by it's position so that we can sort them later.
-					for i, val := range vs.Values {
-						v, ok := val.(*ast.BasicLit)
+					for i, name := range vs.Names {
+						if i >= len(values) || name.Name == "_" {
+							continue
+						}
+						pos := int(name.Pos())
+						value, ok := constValue(values[i], iota)
 						if !ok {
-							beeLogger.Log.Warnf("Unknown type without BasicLit: %v", v)
+							beeLogger.Log.Warnf("Cannot compute the value of the enum %s of %s", name.Name, k)
 							continue
 						}
-						enums[int(val.Pos())] = fmt.Sprintf("%s = %s", vs.Names[i].Name, v.Value)
-						switch v.Kind {
-						case token.INT:
-							vv, err := strconv.Atoi(v.Value)
-							if err != nil {
-								beeLogger.Log.Warnf("Unknown type with BasicLit to int: %v", v.Value)
-								continue
-							}
-							enumValues[int(val.Pos())] = vv
-						case token.FLOAT:
-							vv, err := strconv.ParseFloat(v.Value, 64)
-							if err != nil {
-								beeLogger.Log.Warnf("Unknown type with BasicLit to int: %v", v.Value)
-								continue
-							}
-							enumValues[int(val.Pos())] = vv
-						default:
-							enumValues[int(val.Pos())] = strings.Trim(v.Value, `"`)
+						if v, ok := values[i].(*ast.BasicLit); ok {
+							enums[pos] = fmt.Sprintf("%s = %s", name.Name, v.Value)
+						} else {
+							enums[pos] = fmt.Sprintf("%s = %s", name.Name, value.ExactString())
 						}
-
+						enumValues[pos] = typedConstValue(value, m.Type)
 					}
 				}
 			}
@@ -1196,6 +3905,11 @@ func parseIdent(st *ast.Ident, k string, m *swagger.Schema, astPkgs []*ast.Packa
 			keys = append(keys, k)
 		}
 		sort.Ints(keys)
+		if max := config.Conf.Swagger.MaxEnumValues; max > 0 && len(keys) > max {
+			beeLogger.Log.Warnf("Enum %s has %d values, only the first %d are documented", k, len(keys), max)
+			keys = keys[:max]
+			m.EnumTruncated = true
+		}
 		for _, k := range keys {
 			m.Enum = append(m.Enum, enums[k])
 		}
@@ -1226,7 +3940,21 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 		lm.Properties = make(map[string]swagger.Propertie)
 		lm.AllOf = make([]*swagger.Schema, 0)
 		for _, field := range st.Fields.List {
+			// unexported fields are never encoded, whatever their tags say
+			if len(field.Names) > 0 && !field.Names[0].IsExported() {
+				continue
+			}
+			// an ignored field is skipped before its type is analysed, so that its model is not collected
+			if field.Tag != nil && reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get(config.Conf.Swagger.IgnoreTag) != "" {
+				continue
+			}
 			isSlice, realType, sType := typeAnalyser(packageName, field)
+			if field.Tag != nil {
+				// an explicit type hint wins, e.g. for interface{} fields always holding the same type
+				if hint := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("swaggertype"); hint != "" {
+					isSlice, realType, sType = typeHint(hint)
+				}
+			}
 			if (isSlice && isBasicType(realType)) || sType == astTypeObject {
 				realType = normalizeTypeName(packageName, realType)
 			}
@@ -1235,7 +3963,9 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 			isObject := false
 			if isSlice {
 				mp.Type = astTypeArray
-				if t, ok := basicTypes[(strings.Replace(realType, "[]", "", -1))]; ok {
+				if realType == astTypeMap {
+					mp.Items = mapProperty(astPkgs, packageName, k, field, arrayElt(field.Type), realTypes)
+				} else if t, ok := basicTypes[(strings.Replace(realType, "[]", "", -1))]; ok {
 					typeFormat := strings.Split(t, ":")
 					mp.Items = &swagger.Propertie{
 						Type:   typeFormat[0],
@@ -1255,22 +3985,17 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 					mp.Type = typeFormat[0]
 					mp.Format = typeFormat[1]
 				} else if realType == astTypeMap {
-					typeFormat := strings.Split(sType, ":")
-					mp.Type = astTypeObject
-					if typeFormat[0] == astTypeObject && typeFormat[1] != "" {
-						// map[string]object
-						valType := normalizeTypeName(packageName, typeFormat[1])
-						*realTypes = append(*realTypes, valType)
-						mp.AdditionalProperties = &swagger.Propertie{
-							Ref: "#/definitions/" + valType,
-						}
-					} else {
-						// map[string]basicType
-						mp.AdditionalProperties = &swagger.Propertie{
-							Type:   typeFormat[0],
-							Format: typeFormat[1],
-						}
-					}
+					mp = *mapProperty(astPkgs, packageName, k, field, field.Type, realTypes)
+				}
+			}
+			if star, ok := field.Type.(*ast.StarExpr); ok {
+				switch star.X.(type) {
+				case *ast.ArrayType, *ast.MapType:
+					mp.Nullable = true
+				}
+				if mp.Ref != "" && isNamedScalar(astPkgs, packageName, star.X) {
+					// the siblings of a $ref are ignored, so the reference to the enum is wrapped
+					mp = swagger.Propertie{AllOf: []*swagger.Propertie{{Ref: mp.Ref}}, Nullable: true}
 				}
 			}
 			if field.Names != nil {
@@ -1280,6 +4005,9 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 
 				// if no tag skip tag processing
 				if field.Tag == nil {
+					if config.Conf.Swagger.OmitEmptyOptional {
+						lm.Required = append(lm.Required, name)
+					}
 					lm.Properties[name] = mp
 					continue
 				}
@@ -1299,17 +4027,47 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 						beeLogger.Log.Warnf("Invalid default value: %s", defaultValue)
 					}
 				}
+				if defaultValue := stag.Get("default"); defaultValue != "" {
+					if isObject || isSlice || realType == astTypeMap {
+						// structured defaults are given as JSON, e.g. default:"{\"limit\":10}"
+						var v interface{}
+						if err := json.Unmarshal([]byte(defaultValue), &v); err != nil {
+							beeLogger.Log.Warnf("Invalid JSON default value for %s.%s: %s", k, name, err)
+						} else {
+							if mp.Ref != "" {
+								// the siblings of a $ref are ignored, so the reference to the model is wrapped
+								mp = swagger.Propertie{AllOf: []*swagger.Propertie{{Ref: mp.Ref}}}
+							}
+							mp.Default = v
+						}
+					} else {
+						mp.Default = str2RealType(defaultValue, realType)
+					}
+				}
 
 				tag := stag.Get("json")
 				if tag != "" {
 					tagValues = strings.Split(tag, ",")
 				}
 
+				if xmlTag := stag.Get("xml"); xmlTag != "" && xmlTag != "-" {
+					setXMLFromTag(&mp, xmlTag)
+				}
+
+				// the keys of a map may be constrained, e.g. keypattern:"^[a-z]{2}$" for locale codes
+				if keyPattern := stag.Get("keypattern"); keyPattern != "" {
+					if realType != astTypeMap {
+						beeLogger.Log.Warnf("keypattern is only allowed for map fields: %s.%s", k, name)
+					} else {
+						mp.PatternProperties = map[string]*swagger.Propertie{keyPattern: mp.AdditionalProperties}
+					}
+				}
+
 				// dont add property if json tag first value is "-"
 				if len(tagValues) == 0 || tagValues[0] != "-" {
 
 					// set property name to the left most json tag value only if is not omitempty
-					if len(tagValues) > 0 && tagValues[0] != "omitempty" {
+					if len(tagValues) > 0 && tagValues[0] != "" && tagValues[0] != "omitempty" {
 						name = tagValues[0]
 					}
 
@@ -1319,22 +4077,26 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 							name = ts[0]
 						}
 					}
-					if required := stag.Get("required"); required != "" {
+					constraints := fieldConstraints(stag)
+					if config.Conf.Swagger.OmitEmptyOptional {
+						if !hasOption(tagValues, "omitempty") {
+							lm.Required = append(lm.Required, name)
+						}
+					} else if required := stag.Get("required"); required != "" || constraints["required"] == "true" {
 						lm.Required = append(lm.Required, name)
 					}
 					if desc := stag.Get("description"); desc != "" {
-						mp.Description = desc
+						mp.Description = safeText(desc)
 					}
 
 					if example := stag.Get("example"); example != "" && !isObject && !isSlice {
 						mp.Example = str2RealType(example, realType)
 					}
 
+					setConstraints(&mp, constraints, k, name)
+
 					lm.Properties[name] = mp
 				}
-				if ignore := stag.Get("ignore"); ignore != "" {
-					continue
-				}
 			} else {
 				if sType == astTypeObject {
 					ref := &swagger.Schema{
@@ -1363,18 +4125,13 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 				} else {
 					//if no json tag, expand all fields of the type here
 					nm := &swagger.Schema{}
-					for _, pkg := range astPkgs {
-						for _, fl := range pkg.Files {
-							for nameOfObj, obj := range fl.Scope.Objects {
-								if obj.Name == fmt.Sprint(field.Type) {
-									parseObject(obj, nameOfObj, nm, realTypes, fl, astPkgs, pkg.Name)
-								}
-							}
-						}
+					if embeddedPkg, embeddedName := qualifiedTypeName(packageName, field.Type); embeddedName != "" {
+						findModel(astPkgs, embeddedPkg, embeddedName, nm, realTypes)
 					}
 					for name, p := range nm.Properties {
 						lm.Properties[name] = p
 					}
+					lm.Required = append(lm.Required, nm.Required...)
 					continue
 				}
 			}
@@ -1391,13 +4148,228 @@ func parseStruct(st *ast.StructType, k string, m *swagger.Schema, realTypes *[]s
 	m.Title = k
 }
 
+// constraintKeys lists the validation constraints documented for struct fields
+var constraintKeys = []string{"minItems", "maxItems", "uniqueItems", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf", "minLength", "maxLength"}
+
+// fieldConstraints returns the validation constraints of a field, given in its validate tag,
+// e.g. validate:"minItems=1,maxItems=10,uniqueItems", or in dedicated tags such as minItems:"1".
+// The required, min, max and len rules of go-playground/validator are understood too.
+func fieldConstraints(stag reflect.StructTag) map[string]string {
+	constraints := make(map[string]string)
+	for _, c := range strings.Split(stag.Get("validate"), ",") {
+		kv := strings.SplitN(strings.TrimSpace(c), "=", 2)
+		if len(kv) == 2 {
+			constraints[kv[0]] = kv[1]
+		} else if kv[0] != "" {
+			constraints[kv[0]] = "true"
+		}
+	}
+	for _, key := range constraintKeys {
+		if v, ok := stag.Lookup(key); ok {
+			constraints[key] = v
+		}
+	}
+	return constraints
+}
+
+// setConstraints sets the validation constraints of the property name of the model k,
+// exclusiveMinimum=0 stands for a minimum of 0 which is not allowed itself
+func setConstraints(mp *swagger.Propertie, constraints map[string]string, k, name string) {
+	isNumber := mp.Type == "integer" || mp.Type == "number"
+	// min, max and len of go-playground/validator bound the value of numbers and the length of strings and arrays
+	bounds := map[string][2]string{
+		"integer":    {"minimum", "maximum"},
+		"number":     {"minimum", "maximum"},
+		"string":     {"minLength", "maxLength"},
+		astTypeArray: {"minItems", "maxItems"},
+	}
+	for _, key := range []string{"min", "max", "len"} {
+		v, ok := constraints[key]
+		if !ok {
+			continue
+		}
+		b, ok := bounds[mp.Type]
+		if !ok {
+			beeLogger.Log.Warnf("%s is only allowed for numeric, string or array fields: %s.%s", key, k, name)
+			continue
+		}
+		switch key {
+		case "min":
+			b[1] = ""
+		case "max":
+			b[0] = ""
+		}
+		for _, bound := range b {
+			if _, explicit := constraints[bound]; bound != "" && !explicit {
+				constraints[bound] = v
+			}
+		}
+	}
+	for _, key := range constraintKeys {
+		v, ok := constraints[key]
+		if !ok {
+			continue
+		}
+		switch key {
+		case "minItems", "maxItems", "uniqueItems":
+			if mp.Type != astTypeArray {
+				beeLogger.Log.Warnf("%s is only allowed for array fields: %s.%s", key, k, name)
+				continue
+			}
+		case "minLength", "maxLength":
+			if mp.Type != "string" {
+				beeLogger.Log.Warnf("%s is only allowed for string fields: %s.%s", key, k, name)
+				continue
+			}
+		default:
+			if !isNumber {
+				beeLogger.Log.Warnf("%s is only allowed for numeric fields: %s.%s", key, k, name)
+				continue
+			}
+		}
+		switch key {
+		case "minItems", "maxItems", "minLength", "maxLength":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				beeLogger.Log.Warnf("Invalid %s value for %s.%s: %s", key, k, name, v)
+				continue
+			}
+			switch key {
+			case "minItems":
+				mp.MinItems = &n
+			case "maxItems":
+				mp.MaxItems = &n
+			case "minLength":
+				mp.MinLength = &n
+			case "maxLength":
+				mp.MaxLength = &n
+			}
+		case "uniqueItems":
+			unique, err := strconv.ParseBool(v)
+			if err != nil {
+				beeLogger.Log.Warnf("Invalid %s value for %s.%s: %s", key, k, name, v)
+				continue
+			}
+			mp.UniqueItems = unique
+		default:
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil || (key == "multipleOf" && n <= 0) {
+				beeLogger.Log.Warnf("Invalid %s value for %s.%s: %s", key, k, name, v)
+				continue
+			}
+			switch key {
+			case "minimum":
+				mp.Minimum = &n
+			case "maximum":
+				mp.Maximum = &n
+			case "exclusiveMinimum":
+				mp.Minimum, mp.ExclusiveMinimum = &n, true
+			case "exclusiveMaximum":
+				mp.Maximum, mp.ExclusiveMaximum = &n, true
+			case "multipleOf":
+				mp.MultipleOf = &n
+			}
+		}
+	}
+}
+
+// hasOption reports whether the options following the name of a struct tag, e.g. json:"name,omitempty", include option
+func hasOption(tagValues []string, option string) bool {
+	for i := 1; i < len(tagValues); i++ {
+		if tagValues[i] == option {
+			return true
+		}
+	}
+	return false
+}
+
+// mapKeyType returns the Go type of the keys of the map typ when they are not strings, warning about
+// the field as JSON only has string keys: the keys of typ are encoded as strings restricted to that type.
+func mapKeyType(astPkgs []*ast.Package, packageName, structName string, field *ast.Field, typ ast.Expr) string {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	mt, ok := typ.(*ast.MapType)
+	if !ok {
+		return ""
+	}
+	keyType := types.ExprString(mt.Key)
+	if t, ok := basicTypes[keyType]; ok {
+		if strings.Split(t, ":")[0] == "string" {
+			return ""
+		}
+	} else if namedScalarType(astPkgs, packageName, mt.Key) == "string" {
+		return ""
+	} else if _, ok := mt.Key.(*ast.Ident); ok {
+		keyType = packageName + "." + keyType
+	}
+	fieldName := ""
+	if len(field.Names) > 0 {
+		fieldName = field.Names[0].Name
+	}
+	key := packageName + "." + structName + "." + fieldName + ":" + keyType
+	if !nonStringMapKeys[key] {
+		nonStringMapKeys[key] = true
+		beeLogger.Log.Warnf("Map field %s.%s has keys of type %s, which JSON encodes as strings", structName, fieldName, keyType)
+	}
+	return keyType
+}
+
+// isNamedScalar reports whether expr names a type whose underlying type is a basic one, such as an enum
+func isNamedScalar(astPkgs []*ast.Package, packageName string, expr ast.Expr) bool {
+	return isBasicType(namedScalarType(astPkgs, packageName, expr))
+}
+
+// namedScalarType returns the underlying type of the type named by expr when it is an identifier
+func namedScalarType(astPkgs []*ast.Package, packageName string, expr ast.Expr) string {
+	pkgName, name := qualifiedTypeName(packageName, expr)
+	for _, pkg := range astPkgs {
+		if pkg.Name != pkgName {
+			continue
+		}
+		for _, fl := range pkg.Files {
+			if d, ok := fl.Scope.Objects[name]; ok && d.Kind == ast.Typ {
+				if ts, ok := d.Decl.(*ast.TypeSpec); ok {
+					if ident, ok := ts.Type.(*ast.Ident); ok {
+						return ident.Name
+					}
+				}
+				return ""
+			}
+		}
+	}
+	return ""
+}
+
+// qualifiedTypeName returns the package and name of a named type expression, types without
+// package qualifier belonging to packageName
+func qualifiedTypeName(packageName string, expr ast.Expr) (string, string) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return packageName, t.Name
+	case *ast.SelectorExpr:
+		return fmt.Sprint(t.X), t.Sel.Name
+	case *ast.StarExpr:
+		return qualifiedTypeName(packageName, t.X)
+	}
+	return "", ""
+}
+
 func typeAnalyser(packageName string, f *ast.Field) (isSlice bool, realType, swaggerType string) {
 	if arr, ok := f.Type.(*ast.ArrayType); ok {
 		if isBasicType(fmt.Sprint(arr.Elt)) {
 			return true, fmt.Sprintf("[]%v", arr.Elt), basicTypes[fmt.Sprint(arr.Elt)]
 		}
-		if mp, ok := arr.Elt.(*ast.MapType); ok {
-			return false, fmt.Sprintf("map[%v][%v]", mp.Key, mp.Value), astTypeObject
+		if name := genericTypeName(packageName, arr.Elt); name != "" {
+			return true, name, astTypeObject
+		}
+		elt := arr.Elt
+		if star, ok := elt.(*ast.StarExpr); ok {
+			elt = star.X
+		}
+		if _, ok := elt.(*ast.MapType); ok {
+			// the items are documented by mapProperty
+			return true, astTypeMap, astTypeObject + ":"
 		}
 		if star, ok := arr.Elt.(*ast.StarExpr); ok {
 			basicType := fmt.Sprint(star.X)
@@ -1415,6 +4387,9 @@ func typeAnalyser(packageName string, f *ast.Field) (isSlice bool, realType, swa
 		}
 		return true, fmt.Sprint(arr.Elt), astTypeObject
 	}
+	if name := genericTypeName(packageName, f.Type); name != "" {
+		return false, name, astTypeObject
+	}
 	switch t := f.Type.(type) {
 	case *ast.SelectorExpr:
 		basicType := fmt.Sprintf("%s.%s", t.X, t.Sel.Name)
@@ -1426,6 +4401,11 @@ func typeAnalyser(packageName string, f *ast.Field) (isSlice bool, realType, swa
 		}
 		return false, basicType, astTypeObject
 	case *ast.StarExpr:
+		switch t.X.(type) {
+		case *ast.ArrayType, *ast.MapType:
+			// a pointer to an array or a map is documented as the container itself, made nullable
+			return typeAnalyser(packageName, &ast.Field{Names: f.Names, Type: t.X})
+		}
 		basicType := fmt.Sprint(t.X)
 		if _, ok := t.X.(*ast.StructType); ok {
 			// Interface as Map
@@ -1444,6 +4424,9 @@ func typeAnalyser(packageName string, f *ast.Field) (isSlice bool, realType, swa
 		switch t.Value.(type) {
 		case *ast.InterfaceType:
 			val = "json.RawMessage"
+		case *ast.MapType, *ast.ArrayType:
+			// nested containers are documented by mapValueProperty
+			return false, astTypeMap, astTypeObject + ":"
 		case *ast.StarExpr:
 			val = fmt.Sprint(t.Value.(*ast.StarExpr).X)
 			if object, isStdLibObject := stdlibObject[val]; isStdLibObject {
@@ -1471,6 +4454,212 @@ func typeAnalyser(packageName string, f *ast.Field) (isSlice bool, realType, swa
 	return false, basicType, astTypeObject
 }
 
+// genericTypeName returns the qualified name of an instantiated generic type, pointers to it
+// included, or "" when expr is not one
+func genericTypeName(packageName string, expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch expr.(type) {
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return typeString(qualifyType(packageName, expr))
+	}
+	return ""
+}
+
+// typeString formats a type expression without spaces, e.g. models.Pair[string,models.Profile]
+func typeString(expr ast.Expr) string {
+	return strings.Replace(types.ExprString(expr), ", ", ",", -1)
+}
+
+// mapValueProperty documents the values of the map typ of a field, which may be maps or arrays
+// themselves. Models are only referenced, so that recursive types terminate.
+func mapValueProperty(astPkgs []*ast.Package, packageName, structName string, field *ast.Field, typ ast.Expr, realTypes *[]string) *swagger.Propertie {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	mt, ok := typ.(*ast.MapType)
+	if !ok {
+		return nil
+	}
+	isSlice, realType, _ := typeAnalyser(packageName, &ast.Field{Names: field.Names, Type: mt.Value})
+	if isSlice {
+		items := &swagger.Propertie{}
+		if realType == astTypeMap {
+			items = mapProperty(astPkgs, packageName, structName, field, arrayElt(mt.Value), realTypes)
+		} else if t, ok := basicTypes[strings.TrimPrefix(realType, "[]")]; ok {
+			typeFormat := strings.Split(t, ":")
+			items.Type, items.Format = typeFormat[0], typeFormat[1]
+		} else {
+			realType = normalizeTypeName(packageName, realType)
+			*realTypes = append(*realTypes, realType)
+			items.Ref = "#/definitions/" + realType
+		}
+		return &swagger.Propertie{Type: astTypeArray, Items: items}
+	}
+	if realType == astTypeMap {
+		return mapProperty(astPkgs, packageName, structName, field, mt.Value, realTypes)
+	}
+	if t, ok := basicTypes[realType]; ok {
+		typeFormat := strings.Split(t, ":")
+		return &swagger.Propertie{Type: typeFormat[0], Format: typeFormat[1]}
+	}
+	realType = normalizeTypeName(packageName, realType)
+	*realTypes = append(*realTypes, realType)
+	return &swagger.Propertie{Ref: "#/definitions/" + realType}
+}
+
+// mapProperty documents the map typ, with the schema of its values in additionalProperties
+func mapProperty(astPkgs []*ast.Package, packageName, structName string, field *ast.Field, typ ast.Expr, realTypes *[]string) *swagger.Propertie {
+	return &swagger.Propertie{
+		Type:                 astTypeObject,
+		AdditionalProperties: mapValueProperty(astPkgs, packageName, structName, field, typ, realTypes),
+		KeyType:              mapKeyType(astPkgs, packageName, structName, field, typ),
+	}
+}
+
+// arrayElt returns the type of the elements of the array or slice typ, which may be a pointer
+func arrayElt(typ ast.Expr) ast.Expr {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if arr, ok := typ.(*ast.ArrayType); ok {
+		return arr.Elt
+	}
+	return nil
+}
+
+// setXMLFromTag documents the xml encoding of a property as declared by its xml struct tag,
+// "a>b" wrapping the items of an array in a <a> element and ",attr" making it an attribute.
+func setXMLFromTag(mp *swagger.Propertie, xmlTag string) {
+	tagValues := strings.Split(xmlTag, ",")
+	name := tagValues[0]
+	xml := &swagger.XML{}
+	for _, v := range tagValues[1:] {
+		if v == "attr" {
+			xml.Attribute = true
+		}
+	}
+	if mp.Type == astTypeArray && mp.Items != nil {
+		if names := strings.Split(name, ">"); len(names) > 1 {
+			xml.Name = names[0]
+			xml.Wrapped = true
+			name = names[len(names)-1]
+		}
+		if name != "" && mp.Items.Ref == "" {
+			mp.Items.XML = &swagger.XML{Name: name}
+		}
+	} else {
+		xml.Name = name
+	}
+	if *xml != (swagger.XML{}) {
+		mp.XML = xml
+	}
+}
+
+// isValidDate reports whether s is a full date, a RFC 3339 date-time or an HTTP-date
+func isValidDate(s string) bool {
+	for _, layout := range []string{"2006-01-02", time.RFC3339, http.TimeFormat} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+var contentTypesRegex = regexp.MustCompile(`^((application|text|image|audio|video|multipart|font)/[\w.+-]+,?)+$`)
+
+// splitContentTypes splits the trailing comma separated media types from a response description
+// @Failure 500 server error page text/html
+// @Success 200 {object} models.Foo "ok" application/json,application/xml
+func splitContentTypes(desc string) (string, []string) {
+	i := strings.LastIndexFunc(desc, unicode.IsSpace)
+	last := desc[i+1:]
+	if !contentTypesRegex.MatchString(last) {
+		return desc, nil
+	}
+	var types []string
+	for _, ct := range strings.Split(last, ",") {
+		if ct != "" {
+			types = append(types, ct)
+		}
+	}
+	if i < 0 {
+		return "", types
+	}
+	return strings.TrimSpace(desc[:i]), types
+}
+
+func hasContentType(types []string, contentType string) bool {
+	for _, t := range types {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// typeHint analyses a type name given by a swaggertype struct tag, such as "models.Foo" or "[]string"
+func typeHint(hint string) (isSlice bool, realType, swaggerType string) {
+	if strings.HasPrefix(hint, "[]") {
+		isSlice = true
+		hint = hint[2:]
+	}
+	if k, ok := basicTypes[hint]; ok {
+		if isSlice {
+			return true, "[]" + hint, k
+		}
+		return false, hint, k
+	}
+	return isSlice, hint, astTypeObject
+}
+
+func isScalarType(swaggerType string) bool {
+	return swaggerType == "string" || swaggerType == "integer" || swaggerType == "number" || swaggerType == "boolean"
+}
+
+// inEnum reports whether the value is one of the enum values, whatever their types
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// enumValues returns the values of an enum schema, whose entries are documented as "Name = value"
+func enumValues(m swagger.Schema) []interface{} {
+	var values []interface{}
+	for _, e := range m.Enum {
+		entry, ok := e.(string)
+		if !ok {
+			values = append(values, e)
+			continue
+		}
+		if i := strings.Index(entry, " = "); i >= 0 {
+			entry = entry[i+len(" = "):]
+		}
+		switch m.Type {
+		case "integer":
+			if v, err := strconv.ParseInt(entry, 0, 64); err == nil {
+				values = append(values, v)
+				continue
+			}
+		case "number":
+			if v, err := strconv.ParseFloat(entry, 64); err == nil {
+				values = append(values, v)
+				continue
+			}
+		}
+		if v, err := strconv.Unquote(entry); err == nil {
+			entry = v
+		}
+		values = append(values, entry)
+	}
+	return values
+}
+
 func isBasicType(Type string) bool {
 	if _, ok := basicTypes[Type]; ok {
 		return true
@@ -1505,56 +4694,118 @@ func appendModels(fl *ast.File, pkgpath, controllerName string, realTypes []stri
 			}
 			_, mod, newRealTypes := getModel(fl, realType)
 			modelsList[pkgpath+controllerName][realType] = mod
-			appendModels(fl, pkgpath, controllerName, newRealTypes)
+			// the properties are walked as well, the realTypes of a cached type may be incomplete
+			appendModels(fl, pkgpath, controllerName, append(newRealTypes, schemaRefs(mod)...))
+		}
+	}
+}
+
+// schemaRefs returns the definitions referenced by the properties of a schema
+func schemaRefs(m swagger.Schema) (refs []string) {
+	var walk func(p *swagger.Propertie)
+	walk = func(p *swagger.Propertie) {
+		if p == nil {
+			return
+		}
+		if p.Ref != "" {
+			refs = append(refs, strings.TrimPrefix(p.Ref, "#/definitions/"))
+		}
+		walk(p.Items)
+		walk(p.AdditionalProperties)
+		for _, sub := range p.AllOf {
+			walk(sub)
+		}
+		for _, sub := range p.Properties {
+			walk(&sub)
+		}
+	}
+	for _, p := range m.Properties {
+		walk(&p)
+	}
+	if m.Items != nil {
+		for _, p := range m.Items.Properties {
+			walk(&p)
+		}
+		if m.Items.Ref != "" {
+			refs = append(refs, strings.TrimPrefix(m.Items.Ref, "#/definitions/"))
+		}
+	}
+	for _, s := range m.AllOf {
+		if s != nil {
+			refs = append(refs, schemaRefs(*s)...)
+			if s.Ref != "" {
+				refs = append(refs, strings.TrimPrefix(s.Ref, "#/definitions/"))
+			}
 		}
 	}
+	return
 }
 
-func getSecurity(t string) (security map[string][]string) {
+func getSecurity(value string) (security map[string][]string, err error) {
 	security = make(map[string][]string)
-	p := getparams(strings.TrimSpace(t[len("@Security"):]))
+	p := getparams(value)
 	if len(p) == 0 {
-		beeLogger.Log.Fatalf("No params for security specified\n")
+		return nil, errors.New("no params for security specified")
 	}
 	security[p[0]] = make([]string, 0)
 	for i := 1; i < len(p); i++ {
 		security[p[0]] = append(security[p[0]], p[i])
 	}
-	return
+	return security, nil
 }
 
 func urlReplace(src string) string {
 	pt := strings.Split(src, "/")
 	for i, p := range pt {
 		if len(p) > 0 {
-			if p[0] == ':' {
-				pt[i] = "{" + p[1:] + "}"
-			} else if p[0] == '?' && p[1] == ':' {
-				pt[i] = "{" + p[2:] + "}"
+			switch p {
+			case "*.*":
+				// beego names the matches of /file/*.* :path and :ext
+				pt[i] = "{path}.{ext}"
+				continue
+			case "*":
+				pt[i] = "{splat}"
+				continue
 			}
-
-			if pt[i][0] == '{' && strings.Contains(pt[i], ":") {
-				pt[i] = pt[i][:strings.Index(pt[i], ":")] + "}"
-			} else if pt[i][0] == '{' && strings.Contains(pt[i], "(") {
-				pt[i] = pt[i][:strings.Index(pt[i], "(")] + "}"
+			// a format suffix such as /users/:id.:ext holds a second param
+			exts := strings.SplitN(p, ".:", 2)
+			pt[i] = urlParam(exts[0])
+			if len(exts) > 1 {
+				pt[i] += "." + urlParam(":"+exts[1])
 			}
 		}
 	}
 	return strings.Join(pt, "/")
 }
 
+// urlParam turns a beego path param, optionally typed or constrained by a regexp, into a path template param
+func urlParam(p string) string {
+	if strings.HasPrefix(p, ":") {
+		p = "{" + p[1:] + "}"
+	} else if strings.HasPrefix(p, "?:") {
+		p = "{" + p[2:] + "}"
+	}
+
+	if len(p) > 0 && p[0] == '{' && strings.Contains(p, ":") {
+		p = p[:strings.Index(p, ":")] + "}"
+	} else if len(p) > 0 && p[0] == '{' && strings.Contains(p, "(") {
+		p = p[:strings.Index(p, "(")] + "}"
+	}
+	return p
+}
+
 func str2RealType(s string, typ string) interface{} {
 	var err error
 	var ret interface{}
 
 	switch typ {
-	case "int", "int64", "int32", "int16", "int8":
+	case "int", "int64", "int32", "int16", "int8", "integer":
 		ret, err = strconv.Atoi(s)
 	case "uint", "uint64", "uint32", "uint16", "uint8":
 		ret, err = strconv.ParseUint(s, 10, 0)
-	case "bool":
+	case "bool", "boolean":
 		ret, err = strconv.ParseBool(s)
-	case "float64":
+	case "float64", "number":
 		ret, err = strconv.ParseFloat(s, 64)
 	case "float32":
 		ret, err = strconv.ParseFloat(s, 32)
@@ -1563,7 +4814,7 @@ func str2RealType(s string, typ string) interface{} {
 	}
 
 	if err != nil {
-		beeLogger.Log.Warnf("Invalid default value type '%s': %s", typ, s)
+		beeLogger.Log.Warnf("Invalid value of type '%s': %s", typ, s)
 		return s
 	}
 
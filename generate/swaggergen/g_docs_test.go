@@ -0,0 +1,2098 @@
+// Copyright 2013 bee authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package swaggergen
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/beego/bee/config"
+	"github.com/beego/bee/generate/swaggergen/swagger"
+	beeLogger "github.com/beego/bee/logger"
+	"gopkg.in/yaml.v2"
+)
+
+// buildFixture builds the docs of the application testdata/fixture, whose packages belong to
+// the fixtures module of testdata/go.mod
+func buildFixture(t testing.TB, fixture string) error {
+	t.Helper()
+	t.Setenv("GO111MODULE", "on")
+	t.Setenv("GOWORK", "off")
+	resetDocs()
+	dir, err := filepath.Abs(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// as bee generate docs does, the packages of the application are parsed first
+	ParsePackagesFromDir(dir)
+	return buildDocs(dir)
+}
+
+// generateFixture returns the docs of the application testdata/fixture, as decoded from JSON
+func generateFixture(t *testing.T, fixture string) map[string]interface{} {
+	t.Helper()
+	if err := buildFixture(t, fixture); err != nil {
+		t.Fatalf("Error while generating the docs of %s: %s", fixture, err)
+	}
+	return currentDocs(t)
+}
+
+// currentDocs returns the docs built last, as decoded from JSON
+func currentDocs(t *testing.T) map[string]interface{} {
+	t.Helper()
+	v, err := docsValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var docs map[string]interface{}
+	if err := json.Unmarshal(data, &docs); err != nil {
+		t.Fatal(err)
+	}
+	return docs
+}
+
+// lookup returns the value at the path of keys in v, the keys of arrays being indexes,
+// or nil when there is none
+func lookup(v interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			v = t[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil
+			}
+			v = t[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// captureLog returns the buffer the log is written to until the end of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	beeLogger.Log.SetOutput(&buf)
+	t.Cleanup(func() { beeLogger.Log.SetOutput(os.Stdout) })
+	return &buf
+}
+
+// setSpec generates the docs following spec until the end of the test
+func setSpec(t *testing.T, spec string) {
+	t.Helper()
+	old := Spec
+	Spec = spec
+	t.Cleanup(func() { Spec = old })
+}
+
+func TestIgnoredFields(t *testing.T) {
+	docs := generateFixture(t, "ignore")
+	properties := lookup(docs, "definitions", "models.User", "properties").(map[string]interface{})
+	if _, ok := properties["name"]; !ok {
+		t.Errorf("name is missing from %v", properties)
+	}
+	for _, name := range []string{"password", "secret"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("the ignored %s is documented: %v", name, properties)
+		}
+	}
+	// the model of an ignored field is not collected
+	if secret := lookup(docs, "definitions", "models.Secret"); secret != nil {
+		t.Errorf("the model of the ignored secret is documented: %v", secret)
+	}
+
+	// the tag is configurable
+	old := config.Conf.Swagger.IgnoreTag
+	config.Conf.Swagger.IgnoreTag = "swaggerignore"
+	t.Cleanup(func() { config.Conf.Swagger.IgnoreTag = old })
+	docs = generateFixture(t, "ignore")
+	properties = lookup(docs, "definitions", "models.User", "properties").(map[string]interface{})
+	if _, ok := properties["token"]; ok {
+		t.Errorf("the ignored token is documented: %v", properties)
+	}
+	if _, ok := properties["password"]; !ok {
+		t.Errorf("password is missing from %v", properties)
+	}
+}
+
+func TestDocsErrors(t *testing.T) {
+	if err := buildFixture(t, "missing"); err == nil {
+		t.Error("no error without router.go")
+	}
+
+	// a custom annotation may document a value which cannot be encoded
+	t.Cleanup(func() { Bundle = false })
+	for _, tc := range []struct {
+		spec   string
+		bundle bool
+	}{{"2.0", false}, {"2.0", true}, {"3.0", false}, {"3.0", true}} {
+		resetDocs()
+		setSpec(t, tc.spec)
+		Bundle = tc.bundle
+		rootapi.Paths = map[string]*swagger.Item{
+			"/": {Get: &swagger.Operation{Extensions: swagger.Extensions{"x-callback": func() {}}}},
+		}
+		if _, _, err := encodeDocs(); err == nil {
+			t.Errorf("spec %s, bundle %v: no error while encoding a function", tc.spec, tc.bundle)
+		}
+	}
+}
+
+func TestControllerIncludedTwice(t *testing.T) {
+	docs := generateFixture(t, "twice")
+	ids := make(map[interface{}]string)
+	for path, tag := range map[string]string{"/user/": "user", "/admin/": "admin"} {
+		op := lookup(docs, "paths", path, "get")
+		if op == nil {
+			t.Fatalf("GET %s is not documented", path)
+		}
+		if tags := lookup(op, "tags"); !reflect.DeepEqual(tags, []interface{}{tag}) {
+			t.Errorf("the tags of GET %s are %v, want [%s]", path, tags, tag)
+		}
+		id := lookup(op, "operationId")
+		if other, ok := ids[id]; ok {
+			t.Errorf("GET %s and GET %s have the same operationId %v", path, other, id)
+		}
+		ids[id] = path
+	}
+}
+
+func TestXMLArrayResponses(t *testing.T) {
+	docs := generateFixture(t, "xmlarray")
+	for path, items := range map[string]map[string]interface{}{
+		"/user/":         {"$ref": "#/definitions/models.User"},
+		"/user/statuses": {"type": "string"},
+	} {
+		schema := lookup(docs, "paths", path, "get", "responses", "200", "schema")
+		if got := lookup(schema, "items"); !reflect.DeepEqual(got, items) {
+			t.Errorf("the items of GET %s are %v, want %v", path, got, items)
+		}
+		want := map[string]interface{}{"name": "items", "wrapped": true}
+		if xml := lookup(schema, "xml"); !reflect.DeepEqual(xml, want) {
+			t.Errorf("the xml of GET %s is %v, want %v", path, xml, want)
+		}
+	}
+}
+
+func TestIotaEnums(t *testing.T) {
+	docs := generateFixture(t, "enums")
+	for _, tc := range []struct {
+		name    string
+		enum    []interface{}
+		example float64
+	}{
+		{"models.Status", []interface{}{"Active = 0", "Inactive = 1", "Banned = 3"}, 0},
+		{"models.Permission", []interface{}{"Read = 1", "Write = 2", "Admin = 4"}, 1},
+	} {
+		schema := lookup(docs, "definitions", tc.name)
+		if enum := lookup(schema, "enum"); !reflect.DeepEqual(enum, tc.enum) {
+			t.Errorf("the values of %s are %v, want %v", tc.name, enum, tc.enum)
+		}
+		if example := lookup(schema, "example"); example != tc.example {
+			t.Errorf("the example of %s is %v, want %v", tc.name, example, tc.example)
+		}
+	}
+}
+
+func TestEnumParam(t *testing.T) {
+	docs := generateFixture(t, "enums")
+	param := lookup(docs, "paths", "/user/", "get", "parameters", "0")
+	if typ := lookup(param, "type"); typ != "string" {
+		t.Errorf("the param has the type %v, want string", typ)
+	}
+	if enum := lookup(param, "enum"); !reflect.DeepEqual(enum, []interface{}{"asc", "desc"}) {
+		t.Errorf("the values of the param are %v, want [asc desc]", enum)
+	}
+	if schema := lookup(param, "schema"); schema != nil {
+		t.Errorf("the param refers to %v", schema)
+	}
+	if lookup(docs, "definitions", "models.Sort") != nil {
+		t.Error("the inlined models.Sort is defined")
+	}
+}
+
+func TestHeaderArrayParams(t *testing.T) {
+	docs := generateFixture(t, "headers")
+	params := lookup(docs, "paths", "/proxy/", "get", "parameters")
+	for i, want := range []interface{}{nil, "pipes", "tsv"} {
+		param := lookup(params, strconv.Itoa(i))
+		if typ := lookup(param, "type"); typ != "array" {
+			t.Errorf("the header %v has the type %v, want array", lookup(param, "name"), typ)
+		}
+		if format := lookup(param, "collectionFormat"); format != want {
+			t.Errorf("the header %v has the collectionFormat %v, want %v", lookup(param, "name"), format, want)
+		}
+	}
+
+	setSpec(t, "3.0")
+	log := captureLog(t)
+	docs = generateFixture(t, "headers")
+	params = lookup(docs, "paths", "/proxy/", "get", "parameters")
+	for i, want := range []interface{}{nil, "pipeDelimited", nil} {
+		param := lookup(params, strconv.Itoa(i))
+		if style := lookup(param, "style"); style != want {
+			t.Errorf("the header %v has the style %v, want %v", lookup(param, "name"), style, want)
+		}
+	}
+	if !strings.Contains(log.String(), "The tab-separated values of the header param X-Tabs") {
+		t.Errorf("no warning about X-Tabs in %q", log.String())
+	}
+}
+
+func TestV2Controller(t *testing.T) {
+	docs := generateFixture(t, "v2")
+	if op := lookup(docs, "paths", "/user/{id}", "get"); op == nil {
+		t.Errorf("GET /user/{id} is not documented: %v", lookup(docs, "paths"))
+	}
+}
+
+func TestControllerTypes(t *testing.T) {
+	src := map[string]string{
+		"v1.go": `package controllers
+import "github.com/astaxie/beego"
+type V1Controller struct { beego.Controller }
+type Namespace struct { *beego.Namespace }`,
+		"v2.go": `package controllers
+import (
+	"github.com/beego/beego/v2/server/web"
+	"fixtures/models"
+)
+type BaseController struct { *web.Controller }
+type UserController struct { BaseController }
+type AdminController struct { UserController }
+type Page struct { models.Base }
+type Item struct { Page }`,
+	}
+	fset := token.NewFileSet()
+	pkg := &ast.Package{Name: "controllers", Files: make(map[string]*ast.File)}
+	for name, s := range src {
+		fl, err := parser.ParseFile(fset, name, s, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkg.Files[name] = fl
+	}
+	want := map[string]bool{"V1Controller": true, "BaseController": true, "UserController": true, "AdminController": true}
+	if got := controllerTypes(pkg); !reflect.DeepEqual(got, want) {
+		t.Errorf("the controllers are %v, want %v", got, want)
+	}
+}
+
+func TestContentTypes(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "contenttypes")
+	json := []interface{}{"application/json"}
+	octet := []interface{}{"application/octet-stream"}
+	for _, tc := range []struct {
+		path, method       string
+		consumes, produces interface{}
+	}{
+		// @Accept is dropped from the operations without body
+		{"/user/{id}", "get", nil, json},
+		{"/user/search", "get", nil, nil},
+		{"/user/", "post", json, json},
+		// the media type of a failure is added to the ones of the operation
+		{"/user/import", "post", nil, []interface{}{"application/json", "text/html"}},
+		// the media types of a success response
+		{"/user/{id}/export", "get", nil, []interface{}{"application/json", "application/xml"}},
+		// the defaults of a tag
+		{"/files/{id}", "get", nil, octet},
+		{"/files/", "post", octet, octet},
+		// @Accept wins over the defaults of the tag
+		{"/files/{id}/stat", "get", nil, json},
+		{"/reports/", "get", nil, []interface{}{"application/xml", "application/json"}},
+		{"/reports/", "post", nil, []interface{}{"application/xml", "application/json"}},
+	} {
+		op := lookup(docs, "paths", tc.path, tc.method)
+		if consumes := lookup(op, "consumes"); !reflect.DeepEqual(consumes, tc.consumes) {
+			t.Errorf("%s %s consumes %v, want %v", tc.method, tc.path, consumes, tc.consumes)
+		}
+		if produces := lookup(op, "produces"); !reflect.DeepEqual(produces, tc.produces) {
+			t.Errorf("%s %s produces %v, want %v", tc.method, tc.path, produces, tc.produces)
+		}
+	}
+	for _, warning := range []string{
+		"[UserController.Get] @Accept application/json has no effect on the request",
+		"[UserController.Search] @Accept application/x-www-form-urlencoded has no effect on the request",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("%q is not logged: %s", warning, log)
+		}
+	}
+	if strings.Contains(log.String(), "UserController.Post]") {
+		t.Errorf("@Accept json of POST /user/ is warned about: %s", log)
+	}
+	if desc := lookup(docs, "paths", "/user/{id}/export", "get", "responses", "200", "description"); desc != `"the user"` {
+		t.Errorf("the media types are left in the description %v", desc)
+	}
+	// the arrays produced as xml by default are wrapped too
+	if xml := lookup(docs, "paths", "/reports/", "get", "responses", "200", "schema", "xml", "wrapped"); xml != true {
+		t.Errorf("the xml of the reports is %v", lookup(docs, "paths", "/reports/", "get", "responses", "200", "schema"))
+	}
+
+	// each response of 3.0 is documented with its own media types
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "contenttypes")
+	for _, tc := range []struct {
+		path, method, code string
+		content            []string
+		ref                string
+	}{
+		{"/user/import", "post", "200", []string{"application/json"}, "#/components/schemas/models.User"},
+		{"/user/import", "post", "201", []string{"application/json"}, "#/components/schemas/models.User"},
+		{"/user/import", "post", "500", []string{"text/html"}, ""},
+		{"/user/{id}/export", "get", "200", []string{"application/json", "application/xml"}, "#/components/schemas/models.User"},
+	} {
+		content := lookup(docs, "paths", tc.path, tc.method, "responses", tc.code, "content").(map[string]interface{})
+		var types []string
+		for ct := range content {
+			types = append(types, ct)
+		}
+		sort.Strings(types)
+		if !reflect.DeepEqual(types, tc.content) {
+			t.Errorf("the %s response of %s %s is documented as %v, want %v", tc.code, tc.method, tc.path, types, tc.content)
+		}
+		for _, ct := range types {
+			if ref := lookup(content, ct, "schema", "$ref"); tc.ref != "" && ref != tc.ref {
+				t.Errorf("the %s content of the %s response of %s %s refers to %v, want %s", ct, tc.code, tc.method, tc.path, ref, tc.ref)
+			}
+		}
+	}
+}
+
+func TestSanitizeOperationID(t *testing.T) {
+	for title, want := range map[string]string{
+		"GetUserList":      "GetUserList",
+		"Get User List":    "GetUserList",
+		"get user-list":    "getUserList",
+		"Delete (by id)!":  "DeleteById",
+		"list_users v2":    "list_usersV2",
+		"  Create  User  ": "CreateUser",
+	} {
+		if id := sanitizeOperationID(title); id != want {
+			t.Errorf("the operationId of %q is %q, want %q", title, id, want)
+		}
+	}
+}
+
+func TestWorkspaceModels(t *testing.T) {
+	t.Setenv("GO111MODULE", "on")
+	work, err := filepath.Abs(filepath.Join("testdata", "workspace", "go.work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOWORK", work)
+	resetDocs()
+	if err := buildDocs(filepath.Join(filepath.Dir(work), "app")); err != nil {
+		t.Fatal(err)
+	}
+	docs := currentDocs(t)
+	if ref := lookup(docs, "paths", "/user/{id}", "get", "responses", "200", "schema", "$ref"); ref != "#/definitions/models.User" {
+		t.Errorf("the response refers to %v", ref)
+	}
+	if name := lookup(docs, "definitions", "models.User", "properties", "name"); name == nil {
+		t.Errorf("models.User of the shared module is not parsed: %v", lookup(docs, "definitions"))
+	}
+}
+
+func TestSwaggerTypeTag(t *testing.T) {
+	docs := generateFixture(t, "swaggertype")
+	properties := lookup(docs, "definitions", "models.Event", "properties")
+	if ref := lookup(properties, "payload", "$ref"); ref != "#/definitions/models.User" {
+		t.Errorf("the payload refers to %v, want models.User", ref)
+	}
+	if lookup(docs, "definitions", "models.User") == nil {
+		t.Error("models.User is not defined")
+	}
+	want := map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}
+	if tags := lookup(properties, "tags"); !reflect.DeepEqual(tags, want) {
+		t.Errorf("the tags are %v, want %v", tags, want)
+	}
+	if ref := lookup(properties, "extra", "$ref"); ref == "#/definitions/models.User" {
+		t.Error("the extra field without hint refers to models.User")
+	}
+}
+
+func TestUnknownParamAlias(t *testing.T) {
+	log := captureLog(t)
+	generateFixture(t, "paramalias")
+	if !strings.Contains(log.String(), "[UserController.Get] @Param userId refers to the unknown function parameter 'uid'") {
+		t.Errorf("no warning about the alias uid in %q", log.String())
+	}
+	if strings.Contains(log.String(), "UserController.Delete") {
+		t.Errorf("the alias of UserController.Delete is warned about: %q", log.String())
+	}
+}
+
+func TestJSONDefaults(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "jsondefault")
+	properties := lookup(docs, "definitions", "models.Query", "properties")
+	for name, want := range map[string]interface{}{
+		"paging":  map[string]interface{}{"limit": 10.0},
+		"sort":    []interface{}{"name"},
+		"filters": map[string]interface{}{"status": "active"},
+		"weights": nil,
+	} {
+		if def := lookup(properties, name, "default"); !reflect.DeepEqual(def, want) {
+			t.Errorf("the default of %s is %v, want %v", name, def, want)
+		}
+	}
+	// the siblings of a $ref are ignored
+	if ref := lookup(properties, "paging", "allOf", "0", "$ref"); ref != "#/definitions/models.Paging" {
+		t.Errorf("the paging refers to %v", lookup(properties, "paging"))
+	}
+	if !strings.Contains(log.String(), "Invalid JSON default value for Query.Weights") {
+		t.Errorf("no warning about the default of weights in %q", log.String())
+	}
+}
+
+func TestTypeGraph(t *testing.T) {
+	docs := generateFixture(t, "typegraph")
+	for _, name := range []string{"models.Customer", "models.Carrier", "types.Money", "types.Address"} {
+		if lookup(docs, "definitions", name) == nil {
+			t.Errorf("%s is not defined", name)
+		}
+	}
+	if ref := lookup(docs, "definitions", "models.Carrier", "properties", "next", "$ref"); ref != "#/definitions/models.Customer" {
+		t.Errorf("the next of models.Carrier refers to %v", ref)
+	}
+	// the packages are parsed once whatever the number of references to them
+	parsed := make(map[string]int)
+	for dir := range astCache {
+		parsed[filepath.Base(dir)]++
+	}
+	for _, pkg := range []string{"models", "types"} {
+		if parsed[pkg] != 1 {
+			t.Errorf("the %s package is parsed %d times", pkg, parsed[pkg])
+		}
+	}
+}
+
+func BenchmarkTypeGraph(b *testing.B) {
+	beeLogger.Log.SetOutput(ioutil.Discard)
+	defer beeLogger.Log.SetOutput(os.Stdout)
+	for i := 0; i < b.N; i++ {
+		if err := buildFixture(b, "typegraph"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDescriptionFile(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "descfile")
+	want := "# Listing users\n\nThe users are sorted by *name*.\n\n"
+	if desc := lookup(docs, "paths", "/user/", "get", "description"); desc != want {
+		t.Errorf("the description is %q, want %q", desc, want)
+	}
+	if desc := lookup(docs, "paths", "/user/{id}", "get", "description"); desc != nil {
+		t.Errorf("the description of the missing file is %q", desc)
+	}
+	if !strings.Contains(log.String(), "[UserController.Get] Cannot read the description file") {
+		t.Errorf("no warning about the missing file in %q", log.String())
+	}
+}
+
+func TestControllerTags(t *testing.T) {
+	docs := generateFixture(t, "tags")
+	want := []interface{}{"user", "accounts", "admin"}
+	for path, method := range map[string]string{"/user/": "get", "/user/{id}": "delete"} {
+		if tags := lookup(docs, "paths", path, method, "tags"); !reflect.DeepEqual(tags, want) {
+			t.Errorf("the tags of %s %s are %v, want %v", method, path, tags, want)
+		}
+	}
+	tags := make(map[interface{}]interface{})
+	for _, tag := range lookup(docs, "tags").([]interface{}) {
+		tags[lookup(tag, "name")] = lookup(tag, "description")
+	}
+	if !reflect.DeepEqual(tags, map[interface{}]interface{}{"user": "Operations about users\n", "accounts": nil, "admin": nil}) {
+		t.Errorf("the tags of the API are %v, want %v", tags, want)
+	}
+}
+
+func TestOperationIDWithoutTitle(t *testing.T) {
+	docs := generateFixture(t, "notitle")
+	if id := lookup(docs, "paths", "/user/", "get", "operationId"); id != "UserController.List" {
+		t.Errorf("the operationId is %v, want UserController.List", id)
+	}
+}
+
+func TestCheckDocs(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "check"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GO111MODULE", "on")
+	t.Setenv("GOWORK", "off")
+	t.Cleanup(func() { os.RemoveAll(filepath.Join(dir, "swagger")) })
+	resetDocs()
+	if err := GenerateDocs(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	resetDocs()
+	if upToDate, err := CheckDocs(dir); err != nil || !upToDate {
+		t.Errorf("the docs just generated are not up to date: %v", err)
+	}
+
+	// the committed spec misses an operation added since
+	jsonFile := filepath.Join(dir, "swagger", "swagger.json")
+	data, err := ioutil.ReadFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var docs map[string]interface{}
+	if err := json.Unmarshal(data, &docs); err != nil {
+		t.Fatal(err)
+	}
+	delete(docs["paths"].(map[string]interface{}), "/user/{id}")
+	if data, err = json.MarshalIndent(docs, "", "    "); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(jsonFile, data, 0666); err != nil {
+		t.Fatal(err)
+	}
+	log := captureLog(t)
+	resetDocs()
+	if upToDate, err := CheckDocs(dir); err != nil || upToDate {
+		t.Errorf("the stale docs are up to date: %v", err)
+	}
+	for _, msg := range []string{"swagger/swagger.json is out of date", "+ paths /user/{id}"} {
+		if !strings.Contains(log.String(), msg) {
+			t.Errorf("%q is not logged in %q", msg, log.String())
+		}
+	}
+	if strings.Contains(log.String(), "swagger/swagger.yml is out of date") {
+		t.Errorf("the up to date swagger.yml is reported: %q", log.String())
+	}
+}
+
+func TestUnexportedFields(t *testing.T) {
+	docs := generateFixture(t, "unexported")
+	properties := lookup(docs, "definitions", "models.User", "properties").(map[string]interface{})
+	if _, ok := properties["name"]; !ok || len(properties) != 1 {
+		t.Errorf("the properties are %v, want name only", properties)
+	}
+}
+
+func TestValueReceivers(t *testing.T) {
+	docs := generateFixture(t, "valuerecv")
+	for path, methods := range map[string][]string{"/user/": {"get", "post"}, "/user/{id}": {"delete"}} {
+		for _, method := range methods {
+			if lookup(docs, "paths", path, method) == nil {
+				t.Errorf("%s %s is not documented", method, path)
+			}
+		}
+	}
+}
+
+func TestAPIVersionOption(t *testing.T) {
+	if version := lookup(generateFixture(t, "notitle"), "info", "version"); version != "1.0.0" {
+		t.Errorf("the version is %v, want the 1.0.0 of @APIVersion", version)
+	}
+	APIVersion = "2.3.4"
+	t.Cleanup(func() { APIVersion = "" })
+	if version := lookup(generateFixture(t, "notitle"), "info", "version"); version != "2.3.4" {
+		t.Errorf("the version is %v, want 2.3.4", version)
+	}
+}
+
+func TestDefaultResponse(t *testing.T) {
+	docs := generateFixture(t, "defaultresponse")
+	for method, want := range map[string]string{"delete": "models.Response", "get": "models.User"} {
+		if ref := lookup(docs, "paths", "/user/{id}", method, "responses", "200", "schema", "$ref"); ref != "#/definitions/"+want {
+			t.Errorf("the response of %s refers to %v, want %s", method, ref, want)
+		}
+	}
+	if lookup(docs, "definitions", "models.Response", "properties", "message") == nil {
+		t.Error("models.Response is not defined")
+	}
+}
+
+func TestTestPackageModels(t *testing.T) {
+	docs := generateFixture(t, "testpkg")
+	properties := lookup(docs, "definitions", "models.User", "properties").(map[string]interface{})
+	if _, ok := properties["name"]; !ok || len(properties) != 1 {
+		t.Errorf("models.User has the properties %v, want those of the models package", properties)
+	}
+	// the types only declared by the test package are still found
+	if lookup(docs, "definitions", "models.Sample", "properties", "id") == nil {
+		t.Errorf("models.Sample is not parsed: %v", lookup(docs, "definitions", "models.Sample"))
+	}
+}
+
+func TestMaxEnumValues(t *testing.T) {
+	old := config.Conf.Swagger.MaxEnumValues
+	config.Conf.Swagger.MaxEnumValues = 2
+	t.Cleanup(func() { config.Conf.Swagger.MaxEnumValues = old })
+	log := captureLog(t)
+	docs := generateFixture(t, "enums")
+	status := lookup(docs, "definitions", "models.Status")
+	if enum := lookup(status, "enum"); !reflect.DeepEqual(enum, []interface{}{"Active = 0", "Inactive = 1"}) {
+		t.Errorf("the values of models.Status are %v, want the first 2", enum)
+	}
+	if truncated := lookup(status, "x-enum-truncated"); truncated != true {
+		t.Errorf("models.Status is not marked as truncated: %v", status)
+	}
+	if !strings.Contains(log.String(), "Enum Status has 3 values, only the first 2 are documented") {
+		t.Errorf("no warning about the truncation in %q", log.String())
+	}
+	// the enums within the limit are left as they are
+	if enum := lookup(docs, "paths", "/user/", "get", "parameters", "0", "enum"); !reflect.DeepEqual(enum, []interface{}{"asc", "desc"}) {
+		t.Errorf("the values of the sort param are %v, want [asc desc]", enum)
+	}
+}
+
+func TestEmbeddedStructs(t *testing.T) {
+	docs := generateFixture(t, "embedded")
+	for _, tc := range []struct {
+		model, base string
+		properties  []string
+	}{
+		{"models.User", "models.Base", []string{"id", "name"}},
+		{"models.Order", "audit.Base", []string{"createdBy", "total"}},
+	} {
+		model, want := tc.model, tc.properties
+		if ref := lookup(docs, "definitions", model, "allOf", "0", "$ref"); ref != "#/definitions/"+tc.base {
+			t.Errorf("%s embeds %v, want %s", model, ref, tc.base)
+		}
+		properties := lookup(docs, "definitions", model, "allOf", "1", "properties").(map[string]interface{})
+		var names []string
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("%s has the properties %v, want %v", model, names, want)
+		}
+	}
+}
+
+func TestInlineBodySchema(t *testing.T) {
+	docs := generateFixture(t, "inlinebody")
+	want := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"age":  map[string]interface{}{"type": "integer", "format": "int64"},
+		},
+		"required": []interface{}{"name"},
+	}
+	if schema := lookup(docs, "paths", "/user/", "post", "parameters", "0", "schema"); !reflect.DeepEqual(schema, want) {
+		t.Errorf("the body schema is %v, want %v", schema, want)
+	}
+}
+
+func TestResponsesSharingModel(t *testing.T) {
+	docs := generateFixture(t, "contenttypes")
+	for _, code := range []string{"200", "201"} {
+		if ref := lookup(docs, "paths", "/user/import", "post", "responses", code, "schema", "$ref"); ref != "#/definitions/models.User" {
+			t.Errorf("the %s response refers to %v", code, ref)
+		}
+	}
+	if definitions := lookup(docs, "definitions").(map[string]interface{}); len(definitions) != 1 {
+		t.Errorf("the definitions are %v, want models.User only", definitions)
+	}
+}
+
+func TestCodeSamples(t *testing.T) {
+	docs := generateFixture(t, "codesample")
+	want := []interface{}{
+		map[string]interface{}{"lang": "curl", "source": "curl -X GET https://api.example.com/v1/user/42\n"},
+		map[string]interface{}{"lang": "go", "source": "client.GetUser(42)"},
+	}
+	if samples := lookup(docs, "paths", "/user/{id}", "get", "x-codeSamples"); !reflect.DeepEqual(samples, want) {
+		t.Errorf("the code samples are %v, want %v", samples, want)
+	}
+}
+
+func TestNestedModels(t *testing.T) {
+	docs := generateFixture(t, "nested")
+	for _, name := range []string{"models.Company", "models.Department", "models.Employee", "models.Person", "models.Address"} {
+		if lookup(docs, "definitions", name) == nil {
+			t.Errorf("%s is not defined", name)
+		}
+	}
+}
+
+func TestFlattenedQueryStruct(t *testing.T) {
+	docs := generateFixture(t, "queryflat")
+	params := make(map[interface{}]interface{})
+	for _, p := range lookup(docs, "paths", "/user/search", "get", "parameters").([]interface{}) {
+		if in := lookup(p, "in"); in != "query" {
+			t.Errorf("the param %v is in %v", lookup(p, "name"), in)
+		}
+		params[lookup(p, "name")] = lookup(p, "type")
+	}
+	want := map[interface{}]interface{}{"q": "string", "tags": "array", "limit": "integer", "offset": "integer"}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("the params are %v, want %v", params, want)
+	}
+}
+
+func TestDefaultOutsideEnum(t *testing.T) {
+	log := captureLog(t)
+	generateFixture(t, "enums")
+	if !strings.Contains(log.String(), "[UserController.Search] The default value newest of param order is not one of its enum values") {
+		t.Errorf("no warning about the default of order in %q", log.String())
+	}
+	if strings.Contains(log.String(), "param sort") {
+		t.Errorf("a warning about the valid default of sort in %q", log.String())
+	}
+}
+
+func TestTypedParamEnums(t *testing.T) {
+	docs := generateFixture(t, "enums")
+	params := lookup(docs, "paths", "/user/search", "get", "parameters")
+	for i, tc := range []struct {
+		name string
+		def  interface{}
+		enum []interface{}
+	}{
+		{"order", "newest", []interface{}{"asc", "desc"}},
+		{"sort", "asc", []interface{}{"asc", "desc"}},
+		// decoded from JSON, numbers are float64
+		{"level", 2.0, []interface{}{1.0, 2.0, 3.0}},
+		{"active", true, []interface{}{true, false}},
+		{"ratio", 0.5, []interface{}{0.5, 1.5}},
+	} {
+		param := lookup(params, strconv.Itoa(i))
+		if name := lookup(param, "name"); name != tc.name {
+			t.Fatalf("the param %d is %v, want %s", i, name, tc.name)
+		}
+		if def := lookup(param, "default"); def != tc.def {
+			t.Errorf("the default of %s is %#v, want %#v", tc.name, def, tc.def)
+		}
+		if enum := lookup(param, "enum"); !reflect.DeepEqual(enum, tc.enum) {
+			t.Errorf("the enum of %s is %#v, want %#v", tc.name, enum, tc.enum)
+		}
+	}
+}
+
+func TestScopesOrder(t *testing.T) {
+	for _, spec := range []string{"2.0", "3.0"} {
+		setSpec(t, spec)
+		if err := buildFixture(t, "scopes"); err != nil {
+			t.Fatal(err)
+		}
+		dt, dtyml, err := encodeDocs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, doc := range []string{string(dt), string(dtyml)} {
+			write, read, admin := strings.Index(doc, "write:pets"), strings.Index(doc, "read:pets"), strings.Index(doc, "admin")
+			if write < 0 || !(write < read && read < admin) {
+				t.Errorf("the scopes of the %s docs are not in their declaration order:\n%s", spec, doc)
+			}
+		}
+	}
+}
+
+func TestURLReplace(t *testing.T) {
+	for route, want := range map[string]string{
+		"/users/:id":           "/users/{id}",
+		"/users/:id:int":       "/users/{id}",
+		"/users/:id([0-9]+)":   "/users/{id}",
+		"/users/?:id":          "/users/{id}",
+		"/users/:id.:ext":      "/users/{id}.{ext}",
+		"/users/:id:int.:ext":  "/users/{id}.{ext}",
+		"/file/*.*":            "/file/{path}.{ext}",
+		"/static/*":            "/static/{splat}",
+		"/users/:id/items/:no": "/users/{id}/items/{no}",
+	} {
+		if got := urlReplace(route); got != want {
+			t.Errorf("urlReplace(%q) = %q, want %q", route, got, want)
+		}
+	}
+}
+
+func TestFormatSuffixRoute(t *testing.T) {
+	docs := generateFixture(t, "formatsuffix")
+	params := lookup(docs, "paths", "/user/{id}.{ext}", "get", "parameters")
+	if params == nil {
+		t.Fatalf("no operation at /user/{id}.{ext} in %v", lookup(docs, "paths"))
+	}
+	// the params of the method are found in the path, format suffix included
+	for _, p := range params.([]interface{}) {
+		if in := lookup(p, "in"); in != "path" {
+			t.Errorf("the param %v is in %v, want path", lookup(p, "name"), in)
+		}
+	}
+	if n := len(params.([]interface{})); n != 2 {
+		t.Errorf("the operation has %d params, want id and ext", n)
+	}
+}
+
+func TestInfoFile(t *testing.T) {
+	if err := buildFixture(t, "infofile"); err != nil {
+		t.Fatal(err)
+	}
+	want := swagger.Information{
+		// the annotations of router.go override the file
+		Title:          "the users API",
+		Description:    "the services of the store",
+		Version:        "1.0.0",
+		TermsOfService: "https://example.com/terms",
+		Contact:        swagger.Contact{Name: "the store team", EMail: "store@example.com"},
+		License:        &swagger.License{Name: "Apache 2.0", URL: "https://www.apache.org/licenses/LICENSE-2.0.html"},
+	}
+	if !reflect.DeepEqual(rootapi.Infos, want) {
+		t.Errorf("the info is %+v, want %+v", rootapi.Infos, want)
+	}
+}
+
+func TestParamExample(t *testing.T) {
+	docs := generateFixture(t, "paramexample")
+	limit := lookup(docs, "paths", "/user/", "get", "parameters", "0")
+	if def, example := lookup(limit, "default"), lookup(limit, "x-example"); def != 10.0 || example != 50.0 {
+		t.Errorf("the limit param defaults to %v with the example %v, want 10 and 50", def, example)
+	}
+	body := lookup(docs, "paths", "/user/", "post", "parameters", "0")
+	if example := lookup(body, "schema", "example"); !reflect.DeepEqual(example, map[string]interface{}{"Name": "alice"}) {
+		t.Errorf("the example of the body is %v", example)
+	}
+	if lookup(body, "x-example") != nil {
+		t.Errorf("the example of the body is documented out of its schema: %v", body)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "paramexample")
+	limit = lookup(docs, "paths", "/user/", "get", "parameters", "0")
+	if def, example := lookup(limit, "schema", "default"), lookup(limit, "example"); def != 10.0 || example != 50.0 {
+		t.Errorf("the limit param defaults to %v with the example %v, want 10 and 50", def, example)
+	}
+}
+
+func TestRecursiveMaps(t *testing.T) {
+	docs := generateFixture(t, "maps")
+	properties := lookup(docs, "definitions", "models.TreeNode", "properties")
+	ref := "#/definitions/models.TreeNode"
+	for _, keys := range [][]string{
+		{"Children", "additionalProperties", "$ref"},
+		{"Groups", "additionalProperties", "items", "$ref"},
+		{"Nested", "additionalProperties", "additionalProperties", "$ref"},
+	} {
+		if got := lookup(properties, keys...); got != ref {
+			t.Errorf("%s is %v, want %s", strings.Join(keys, "."), got, ref)
+		}
+	}
+}
+
+func TestStrictEmptyDefinitions(t *testing.T) {
+	log := captureLog(t)
+	if err := buildFixture(t, "strict"); err != nil {
+		t.Fatalf("the docs fail without the strict mode: %s", err)
+	}
+
+	Strict = true
+	t.Cleanup(func() { Strict = false })
+	err := buildFixture(t, "strict")
+	if err == nil || err.Error() != "found 1 empty definitions" {
+		t.Errorf("the strict mode fails with %v, want 1 empty definition", err)
+	}
+	if !strings.Contains(log.String(), "Definition models.Missing has no properties") {
+		t.Errorf("no error about the unresolved type in %q", log.String())
+	}
+	// the intentionally empty types are allowed
+	if strings.Contains(log.String(), "Definition models.Ack") {
+		t.Errorf("an error about the @EmptyModel type in %q", log.String())
+	}
+}
+
+func TestFileDownload(t *testing.T) {
+	docs := generateFixture(t, "download")
+	for path, produces := range map[string]string{
+		"/file/{id}":   "application/octet-stream",
+		"/file/report": "application/pdf",
+	} {
+		op := lookup(docs, "paths", path, "get")
+		schema := lookup(op, "responses", "200", "schema")
+		if !reflect.DeepEqual(schema, map[string]interface{}{"type": "string", "format": "binary"}) {
+			t.Errorf("the download of %s is documented as %v, want a binary string", path, schema)
+		}
+		if got := lookup(op, "produces"); !reflect.DeepEqual(got, []interface{}{produces}) {
+			t.Errorf("the download of %s produces %v, want %s", path, got, produces)
+		}
+	}
+}
+
+func TestAnnotationHandler(t *testing.T) {
+	RegisterAnnotation("Audit", func(op *swagger.Operation, value string) {
+		op.Extensions["x-audit"] = value
+	})
+	defer delete(annotationHandlers, "@Audit")
+	docs := generateFixture(t, "audit")
+	op := lookup(docs, "paths", "/user/", "delete")
+	if audit := lookup(op, "x-audit"); audit != "user deletion" {
+		t.Errorf("x-audit is %v, want user deletion", audit)
+	}
+	// the annotations without handler are ignored
+	for key := range op.(map[string]interface{}) {
+		if strings.Contains(strings.ToLower(key), "feature") {
+			t.Errorf("the unregistered @FeatureFlag is documented as %s", key)
+		}
+	}
+}
+
+func TestRootAnnotationPrefixes(t *testing.T) {
+	// the annotations are declared shortest first, which used to be matched by their prefix
+	docs := generateFixture(t, "rootannotations")
+	license := lookup(docs, "info", "license")
+	want := map[string]interface{}{"name": "Apache 2.0", "url": "https://www.apache.org/licenses/LICENSE-2.0.html"}
+	if !reflect.DeepEqual(license, want) {
+		t.Errorf("the license is %v, want %v", license, want)
+	}
+	if typ := lookup(docs, "securityDefinitions", "api_key", "type"); typ != "apiKey" {
+		t.Errorf("the api_key security definition is %v", lookup(docs, "securityDefinitions"))
+	}
+	security := []interface{}{map[string]interface{}{"api_key": []interface{}{}}}
+	if got := lookup(docs, "security"); !reflect.DeepEqual(got, security) {
+		t.Errorf("the security of the docs is %v, want %v", got, security)
+	}
+	if got := lookup(docs, "paths", "/user/", "get", "security"); !reflect.DeepEqual(got, security) {
+		t.Errorf("the security of the operation is %v, want %v", got, security)
+	}
+}
+
+func TestHasAnnotation(t *testing.T) {
+	for _, tc := range []struct {
+		comment, annotation string
+		want                bool
+	}{
+		{"@Security api_key", "@Security", true},
+		{"@Security", "@Security", true},
+		{"@Security\tapi_key", "@Security", true},
+		{"@SecurityDefinition api_key apiKey X-API-Key header", "@Security", false},
+		{"@SecurityDefinition api_key apiKey X-API-Key header", "@SecurityDefinition", true},
+		{"@License Apache 2.0", "@License", true},
+		{"@LicenseUrl https://www.apache.org/licenses/LICENSE-2.0.html", "@License", false},
+		{"@LicenseUrl https://www.apache.org/licenses/LICENSE-2.0.html", "@LicenseUrl", true},
+		{"@Title", "@TitleX", false},
+	} {
+		if got := hasAnnotation(tc.comment, tc.annotation); got != tc.want {
+			t.Errorf("hasAnnotation(%q, %q) = %v, want %v", tc.comment, tc.annotation, got, tc.want)
+		}
+	}
+}
+
+// refs returns the sorted references found in v
+func refs(v interface{}) (found []string) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, value := range t {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				found = append(found, ref)
+			} else {
+				found = append(found, refs(value)...)
+			}
+		}
+	case []interface{}:
+		for _, value := range t {
+			found = append(found, refs(value)...)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+func TestBundle(t *testing.T) {
+	Bundle = true
+	t.Cleanup(func() { Bundle = false })
+	docs := generateFixture(t, "nested")
+	if found := refs(docs); len(found) > 0 {
+		t.Errorf("the bundled docs still refer to %v", found)
+	}
+	if definitions := lookup(docs, "definitions"); definitions != nil {
+		t.Errorf("the bundled docs keep the definitions %v", definitions)
+	}
+	if lookup(docs, "paths", "/company/{id}", "get", "responses", "200", "schema", "properties") == nil {
+		t.Errorf("the company is not inlined in the response: %v", lookup(docs, "paths"))
+	}
+
+	// a recursive definition can only be referenced
+	docs = generateFixture(t, "maps")
+	for _, ref := range refs(docs) {
+		if ref != "#/definitions/models.TreeNode" {
+			t.Errorf("the bundled docs refer to %s", ref)
+		}
+	}
+	definitions := lookup(docs, "definitions").(map[string]interface{})
+	if _, ok := definitions["models.TreeNode"]; !ok || len(definitions) != 1 {
+		t.Errorf("the bundled docs define %v, want models.TreeNode only", definitions)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "nested")
+	if found := refs(docs); len(found) > 0 {
+		t.Errorf("the bundled 3.0 docs still refer to %v", found)
+	}
+}
+
+func TestMapKeyPattern(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "maps")
+	properties := lookup(docs, "definitions", "models.Catalog", "properties")
+	for name, want := range map[string]map[string]interface{}{
+		"names":  {"type": "string"},
+		"labels": {"$ref": "#/definitions/models.Label"},
+	} {
+		patterns := lookup(properties, name, "x-patternProperties")
+		if got := lookup(patterns, "^[a-z]{2}$"); !reflect.DeepEqual(got, want) {
+			t.Errorf("the pattern properties of %s are %v, want %v for ^[a-z]{2}$", name, patterns, want)
+		}
+	}
+	if patterns := lookup(properties, "count", "x-patternProperties"); patterns != nil {
+		t.Errorf("the integer count has the pattern properties %v", patterns)
+	}
+	if !strings.Contains(log.String(), "keypattern is only allowed for map fields: Catalog.Count") {
+		t.Errorf("no warning about the keypattern of count in %q", log.String())
+	}
+}
+
+func TestNonStringMapKeys(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "maps")
+	properties := lookup(docs, "definitions", "models.Catalog", "properties")
+	for _, tc := range []struct {
+		field   string
+		keyType interface{}
+		warning string
+	}{
+		{"stock", "int", "Map field Catalog.Stock has keys of type int, which JSON encodes as strings"},
+		{"codes", "models.Code", "Map field Catalog.Codes has keys of type models.Code, which JSON encodes as strings"},
+		// the keys of a named string type are strings
+		{"locales", nil, "Catalog.Locales"},
+		{"labels", nil, "Catalog.Labels"},
+	} {
+		if keyType := lookup(properties, tc.field, "x-key-type"); keyType != tc.keyType {
+			t.Errorf("the key type of %s is %v, want %v", tc.field, keyType, tc.keyType)
+		}
+		if warned := strings.Contains(log.String(), tc.warning); warned != (tc.keyType != nil) {
+			t.Errorf("the keys of %s are warned about: %v, want %v: %s", tc.field, warned, tc.keyType != nil, log)
+		}
+	}
+	if values := lookup(properties, "stock", "additionalProperties", "$ref"); values != "#/definitions/models.Label" {
+		t.Errorf("the values of stock are %v", lookup(properties, "stock"))
+	}
+}
+
+func TestNestedMaps(t *testing.T) {
+	docs := generateFixture(t, "maps")
+	label := map[string]interface{}{"$ref": "#/definitions/models.Label"}
+	mapOf := func(values interface{}) interface{} {
+		return map[string]interface{}{"type": "object", "additionalProperties": values}
+	}
+	arrayOf := func(items interface{}) interface{} {
+		return map[string]interface{}{"type": "array", "items": items}
+	}
+	properties := lookup(docs, "definitions", "models.Catalog", "properties")
+	for field, want := range map[string]interface{}{
+		"sections": mapOf(mapOf(label)),
+		"shelves":  mapOf(arrayOf(label)),
+		"pages":    arrayOf(mapOf(label)),
+	} {
+		if got := lookup(properties, field); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s is %v, want %v", field, got, want)
+		}
+	}
+}
+
+func TestUnparsedControllerPackage(t *testing.T) {
+	// the vendored controllers are not found by their real path, their package is named
+	// after the last element of its import path
+	docs := generateFixture(t, "vendored")
+	if _, ok := importlist[""]; ok {
+		t.Errorf("an import is registered without name: %v", importlist)
+	}
+	if pkg := importlist["controllers"]; pkg != "example.com/shop/controllers" {
+		t.Errorf("the controllers are imported as %q", pkg)
+	}
+	if lookup(docs, "paths", "/user/", "get") == nil {
+		t.Errorf("the vendored controller is not documented: %v", lookup(docs, "paths"))
+	}
+}
+
+func TestPathSecurity(t *testing.T) {
+	old := config.Conf.Swagger.PathSecurity
+	t.Cleanup(func() { config.Conf.Swagger.PathSecurity = old })
+	conf := `{"path_security": [{"Path": "/v1/admin/*", "Security": ["api_key"]}]}`
+	if err := json.Unmarshal([]byte(conf), &config.Conf.Swagger); err != nil {
+		t.Fatal(err)
+	}
+	docs := generateFixture(t, "filterauth")
+	for _, tc := range []struct {
+		path, method string
+		security     interface{}
+	}{
+		{"/admin/accounts", "get", []interface{}{map[string]interface{}{"api_key": []interface{}{}}}},
+		// the annotations win over the filters
+		{"/admin/accounts", "delete", []interface{}{map[string]interface{}{"basic_auth": []interface{}{}}}},
+		{"/user/", "get", nil},
+	} {
+		if got := lookup(docs, "paths", tc.path, tc.method, "security"); !reflect.DeepEqual(got, tc.security) {
+			t.Errorf("the security of %s %s is %v, want %v", tc.method, tc.path, got, tc.security)
+		}
+	}
+}
+
+func TestCRLFFiles(t *testing.T) {
+	// the files read along with the comments were written with CRLF line endings
+	docs := generateFixture(t, "crlf")
+	op := lookup(docs, "paths", "/user/{id}", "get")
+	if desc, want := lookup(op, "description"), "# Getting a user\n\nThe user is found by its *id*.\n\n"; desc != want {
+		t.Errorf("the description is %q, want %q", desc, want)
+	}
+	if source, want := lookup(op, "x-codeSamples", "0", "source"), "curl -X GET \\\n  https://api.example.com/v1/user/42\n"; source != want {
+		t.Errorf("the code sample is %q, want %q", source, want)
+	}
+
+	work := "go 1.18\r\n\r\nuse (\r\n\t./app\r\n\t./shared // the models\r\n)\r\n"
+	if dirs := parseModDirective(work, "use"); !reflect.DeepEqual(dirs, []string{"./app", "./shared"}) {
+		t.Errorf("the modules of the CRLF go.work are %q, want [./app ./shared]", dirs)
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	docs := generateFixture(t, "respheaders")
+	requestID := map[string]interface{}{"type": "string", "description": "the id of the request"}
+	for _, tc := range []struct{ method, code string }{{"get", "500"}, {"delete", "204"}} {
+		headers := lookup(docs, "paths", "/user/", tc.method, "responses", tc.code, "headers")
+		if !reflect.DeepEqual(headers, map[string]interface{}{"X-Request-Id": requestID}) {
+			t.Errorf("the headers of the %s response of %s are %v, want X-Request-Id only", tc.code, tc.method, headers)
+		}
+	}
+	// the headers of the operations override the global ones
+	headers := lookup(docs, "paths", "/user/", "get", "responses", "200", "headers")
+	if desc := lookup(headers, "X-Request-Id", "description"); desc != "the id of the listing" {
+		t.Errorf("X-Request-Id is described as %v, want the one of the operation", desc)
+	}
+	if typ := lookup(headers, "X-Rate-Limit", "type"); typ != "integer" {
+		t.Errorf("X-Rate-Limit is a %v, want an integer", typ)
+	}
+}
+
+func TestFreeFormBody(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "freeform")
+	params := lookup(docs, "paths", "/event/", "post", "parameters")
+	if schema := lookup(params, "0", "schema"); !reflect.DeepEqual(schema, map[string]interface{}{"type": "object"}) {
+		t.Errorf("the schema of the body is %v, want an open object", schema)
+	}
+	if def := lookup(docs, "definitions"); def != nil {
+		t.Errorf("the free-form body is defined as %v", def)
+	}
+	// objects are not allowed out of the body
+	if typ := lookup(params, "1", "type"); typ != "string" {
+		t.Errorf("the meta query param is a %v, want a string", typ)
+	}
+	if !strings.Contains(log.String(), "Only body params may be objects, meta is documented as a string") {
+		t.Errorf("no warning about the meta query param in %q", log.String())
+	}
+}
+
+func TestYAMLHostileText(t *testing.T) {
+	if err := buildFixture(t, "yamlhostile"); err != nil {
+		t.Fatal(err)
+	}
+	dt, dtyml, err := encodeDocs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON map[string]interface{}
+	var fromYAML interface{}
+	if err := json.Unmarshal(dt, &fromJSON); err != nil {
+		t.Fatalf("the JSON docs do not parse: %s", err)
+	}
+	if err := yaml.Unmarshal(dtyml, &fromYAML); err != nil {
+		t.Fatalf("the YAML docs do not parse: %s", err)
+	}
+	for _, tc := range []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"info", "description"}, "@users: - the\tusers # of the *store* | > {x: [y]} 'quoted' \"double\""},
+		// the BOM and the control characters are dropped, the line separators become LF
+		{[]string{"paths", "/user/", "get", "description"}, "@list:\tthe users\n- sorted[0m by name\n# done\uFFFD\n\n"},
+	} {
+		if got := lookup(fromJSON, tc.keys...); got != tc.want {
+			t.Errorf("the JSON %s is %q, want %q", strings.Join(tc.keys, "."), got, tc.want)
+		}
+		got := fromYAML
+		for _, key := range tc.keys {
+			got, _ = got.(map[interface{}]interface{})[key]
+		}
+		if got != tc.want {
+			t.Errorf("the YAML %s is %q, want %q", strings.Join(tc.keys, "."), got, tc.want)
+		}
+	}
+}
+
+func TestControllersSharingPath(t *testing.T) {
+	docs := generateFixture(t, "sharedpath")
+	item := lookup(docs, "paths", "/users/{id}")
+	for method, id := range map[string]string{"get": "UserController.GetUser", "delete": "AdminController.DeleteUser"} {
+		if got := lookup(item, method, "operationId"); got != id {
+			t.Errorf("%s /users/{id} is %v, want %s", method, got, id)
+		}
+	}
+}
+
+func TestArrayConstraints(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "constraints")
+	properties := lookup(docs, "definitions", "models.Order", "properties")
+	for _, tc := range []struct {
+		name, key string
+		want      interface{}
+	}{
+		{"items", "minItems", 1.0},
+		{"items", "maxItems", 10.0},
+		{"items", "uniqueItems", true},
+		// the dedicated tags are read as well, 0 being a valid minimum
+		{"tags", "minItems", 0.0},
+		{"tags", "uniqueItems", nil},
+		{"notes", "maxItems", nil},
+		{"name", "minItems", nil},
+	} {
+		if got := lookup(properties, tc.name, tc.key); got != tc.want {
+			t.Errorf("the %s of %s is %v, want %v", tc.key, tc.name, got, tc.want)
+		}
+	}
+	for _, warning := range []string{
+		"Invalid maxItems value for Order.notes: -1",
+		"minItems is only allowed for array fields: Order.name",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("no warning %q in %q", warning, log.String())
+		}
+	}
+}
+
+func TestNumericConstraints(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "constraints")
+	properties := lookup(docs, "definitions", "models.Order", "properties")
+	for _, tc := range []struct {
+		name, key string
+		want      interface{}
+	}{
+		{"price", "multipleOf", 0.5},
+		{"price", "minimum", 0.0},
+		{"price", "exclusiveMinimum", true},
+		{"price", "maximum", 1000.0},
+		{"price", "exclusiveMaximum", true},
+		{"quantity", "minimum", 1.0},
+		{"quantity", "maximum", 99.0},
+		{"quantity", "exclusiveMinimum", nil},
+		{"quantity", "multipleOf", nil},
+		{"code", "multipleOf", nil},
+	} {
+		if got := lookup(properties, tc.name, tc.key); got != tc.want {
+			t.Errorf("the %s of %s is %v, want %v", tc.key, tc.name, got, tc.want)
+		}
+	}
+	for _, warning := range []string{
+		"Invalid multipleOf value for Order.quantity: 0",
+		"multipleOf is only allowed for numeric fields: Order.code",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("no warning %q in %q", warning, log.String())
+		}
+	}
+}
+
+func TestGenericResponses(t *testing.T) {
+	docs := generateFixture(t, "generics")
+	user := map[string]interface{}{"$ref": "#/definitions/models.User"}
+	for _, tc := range []struct {
+		path, model string
+		data        map[string]interface{}
+	}{
+		{"/user/", "models.Response-array-models.User", map[string]interface{}{"type": "array", "items": user}},
+		{"/user/index", "models.Response-map-string-models.User", map[string]interface{}{"type": "object", "additionalProperties": user}},
+	} {
+		if ref := lookup(docs, "paths", tc.path, "get", "responses", "200", "schema", "$ref"); ref != "#/definitions/"+tc.model {
+			t.Errorf("GET %s responds with %v, want %s", tc.path, ref, tc.model)
+		}
+		if data := lookup(docs, "definitions", tc.model, "properties", "Data"); !reflect.DeepEqual(data, tc.data) {
+			t.Errorf("the data of %s is %v, want %v", tc.model, data, tc.data)
+		}
+	}
+	if lookup(docs, "definitions", "models.User") == nil {
+		t.Error("the type argument models.User is not defined")
+	}
+}
+
+func TestGenericInstances(t *testing.T) {
+	docs := generateFixture(t, "generics")
+	for _, tc := range []struct {
+		path, model string
+		keys        []string
+		want        interface{}
+	}{
+		{"/user/page", "models.Page-models.User", []string{"Items", "items", "$ref"}, "#/definitions/models.User"},
+		{"/user/membership", "models.Pair-models.User-models.Group", []string{"Key", "$ref"}, "#/definitions/models.User"},
+		{"/user/membership", "models.Pair-models.User-models.Group", []string{"Value", "$ref"}, "#/definitions/models.Group"},
+		// a field of an instantiated type refers to its definition
+		{"/user/team", "models.Team", []string{"Members", "$ref"}, "#/definitions/models.Page-models.User"},
+	} {
+		if ref := lookup(docs, "paths", tc.path, "get", "responses", "200", "schema", "$ref"); ref != "#/definitions/"+tc.model {
+			t.Errorf("GET %s responds with %v, want %s", tc.path, ref, tc.model)
+		}
+		keys := append([]string{"definitions", tc.model, "properties"}, tc.keys...)
+		if got := lookup(docs, keys...); got != tc.want {
+			t.Errorf("%s of %s is %v, want %v", strings.Join(tc.keys, "."), tc.model, got, tc.want)
+		}
+	}
+	for name := range lookup(docs, "definitions").(map[string]interface{}) {
+		if strings.ContainsAny(name, "[]") {
+			t.Errorf("the definition %s is named with brackets", name)
+		}
+	}
+}
+
+func TestInstanceName(t *testing.T) {
+	for _, tc := range []struct {
+		expr, want string
+	}{
+		{"models.Page[models.User]", "models.Page-models.User"},
+		{"models.Pair[string, *models.User]", "models.Pair-string-models.User"},
+		{"models.Response[[]models.Page[models.User]]", "models.Response-array-models.Page-models.User"},
+		{"models.Response[map[string][]int]", "models.Response-map-string-array-int"},
+	} {
+		expr, err := parser.ParseExpr(tc.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := instanceName(expr); name != tc.want {
+			t.Errorf("instanceName(%s) = %s, want %s", tc.expr, name, tc.want)
+		}
+	}
+}
+
+func TestBodyExampleFile(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "bodyexample")
+	schema := lookup(docs, "paths", "/user/", "post", "parameters", "0", "schema")
+	if ref := lookup(schema, "$ref"); ref != "#/definitions/models.CreateUser" {
+		t.Errorf("the body refers to %v, want models.CreateUser", ref)
+	}
+	want := map[string]interface{}{"name": "alice", "email": "alice@example.com", "age": 30.0}
+	if example := lookup(schema, "example"); !reflect.DeepEqual(example, want) {
+		t.Errorf("the example of the body is %v, want %v", example, want)
+	}
+	if strings.Contains(log.String(), "[UserController.Post]") {
+		t.Errorf("a warning about the valid example in %q", log.String())
+	}
+	for _, warning := range []string{
+		"[UserController.Put] The example of param body does not match its schema: the example misses the required property email",
+		"[UserController.Put] The example of param body does not match its schema: the example has the unknown property nickname",
+		"[UserController.Import] Cannot read the example file of param body",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("no warning %q in %q", warning, log.String())
+		}
+	}
+	if example := lookup(docs, "paths", "/user/import", "post", "parameters", "0", "schema", "example"); example != nil {
+		t.Errorf("the missing example file is documented as %v", example)
+	}
+}
+
+func TestLiteralEnums(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "literalenum")
+	for model, want := range map[string][]interface{}{
+		// the keys of a map, constants included
+		"models.Status": {"active", "archived"},
+		// the elements of a slice
+		"models.Role": {1.0, 2.0, 4.0},
+	} {
+		if enum := lookup(docs, "definitions", model, "enum"); !reflect.DeepEqual(enum, want) {
+			t.Errorf("the values of %s are %v, want %v", model, enum, want)
+		}
+	}
+	if enum := lookup(docs, "definitions", "models.Kind", "enum"); enum != nil {
+		t.Errorf("the values of models.Kind are %v, want none", enum)
+	}
+	if !strings.Contains(log.String(), "Cannot find the map or slice literal of @Enum models.Kinds") {
+		t.Errorf("no warning about the missing literal in %q", log.String())
+	}
+}
+
+func TestSymlinkedPackages(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "models")
+	if err := os.Mkdir(real, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(real, "user.go"), []byte("package models\n\ntype User struct{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "linked")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("symbolic links are not supported: %s", err)
+	}
+	realPath := canonicalPath(real)
+	if !filepath.IsAbs(realPath) {
+		t.Errorf("the canonical path of %s is %s, want an absolute path", real, realPath)
+	}
+	if got := canonicalPath(link); got != realPath {
+		t.Errorf("the canonical path of the link is %s, want %s", got, realPath)
+	}
+	if got := canonicalPath(filepath.Join(dir, "missing")); got != "" {
+		t.Errorf("the canonical path of a missing directory is %s", got)
+	}
+
+	// the package is parsed once, whichever way it is reached
+	resetDocs()
+	viaReal, err := parseDir(real)
+	if err != nil {
+		t.Fatal(err)
+	}
+	viaLink, err := parseDir(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if viaReal["models"] == nil || viaReal["models"] != viaLink["models"] {
+		t.Errorf("the package is parsed as %v and %v through the link", viaReal, viaLink)
+	}
+
+	// the docs of an application reached through a link are the same
+	want := generateFixture(t, "nested")
+	appLink := filepath.Join("testdata", "linkednested")
+	if err := os.Symlink("nested", appLink); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(appLink)
+	if docs := generateFixture(t, "linkednested"); !reflect.DeepEqual(docs, want) {
+		t.Errorf("the docs of the linked application are\n%v\nwant\n%v", docs, want)
+	}
+}
+
+func TestInfoExtensions(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "infoext")
+	info := lookup(docs, "info")
+	if logo := lookup(info, "x-logo"); !reflect.DeepEqual(logo, map[string]interface{}{"url": "https://example.com/logo.png"}) {
+		t.Errorf("x-logo is %v", logo)
+	}
+	if audience := lookup(info, "x-audience"); audience != "public" {
+		t.Errorf("x-audience is %v, want public", audience)
+	}
+	if _, ok := info.(map[string]interface{})["logo"]; ok {
+		t.Errorf("the extension without x- is documented: %v", info)
+	}
+	// the terms of service are kept, though they are not a valid URL
+	if terms := lookup(info, "termsOfService"); terms != "example.com/terms" {
+		t.Errorf("the terms of service are %v", terms)
+	}
+	for _, warning := range []string{
+		"@TermsOfServiceUrl is not a valid URL: example.com/terms",
+		"The name of @InfoExtension logo should start with x-",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("no warning %q in %q", warning, log.String())
+		}
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "infoext")
+	if audience := lookup(docs, "info", "x-audience"); audience != "public" {
+		t.Errorf("x-audience of the 3.0 docs is %v, want public", audience)
+	}
+}
+
+func TestOmitEmptyOptional(t *testing.T) {
+	old := config.Conf.Swagger.OmitEmptyOptional
+	t.Cleanup(func() { config.Conf.Swagger.OmitEmptyOptional = old })
+	for _, tc := range []struct {
+		option   bool
+		required []interface{}
+	}{
+		// the required tags
+		{false, []interface{}{"name", "email"}},
+		// the fields without omitempty
+		{true, []interface{}{"id", "name", "Age"}},
+	} {
+		config.Conf.Swagger.OmitEmptyOptional = tc.option
+		docs := generateFixture(t, "omitempty")
+		required, _ := lookup(docs, "definitions", "models.User", "required").([]interface{})
+		sort.Slice(required, func(i, j int) bool { return required[i].(string) < required[j].(string) })
+		sort.Slice(tc.required, func(i, j int) bool { return tc.required[i].(string) < tc.required[j].(string) })
+		if !reflect.DeepEqual(required, tc.required) {
+			t.Errorf("omitempty_optional %v: the required fields are %v, want %v", tc.option, required, tc.required)
+		}
+	}
+}
+
+func TestNamedListResponses(t *testing.T) {
+	docs := generateFixture(t, "namedlist")
+	user := map[string]interface{}{"$ref": "#/definitions/models.User"}
+	for path, items := range map[string]interface{}{
+		"/user/":      user,
+		"/user/refs":  user,
+		"/user/names": map[string]interface{}{"type": "string"},
+	} {
+		want := map[string]interface{}{"type": "array", "items": items}
+		if schema := lookup(docs, "paths", path, "get", "responses", "200", "schema"); !reflect.DeepEqual(schema, want) {
+			t.Errorf("GET %s responds with %v, want %v", path, schema, want)
+		}
+	}
+}
+
+func TestEnumsAcrossFiles(t *testing.T) {
+	docs := generateFixture(t, "splitenum")
+	for model, want := range map[string][]interface{}{
+		// the consts are declared by other files than the type
+		"models.Color": {`Red = "red"`, `Blue = "blue"`, `Green = "green"`},
+		// the consts of a type of the same name in another package are not mixed up
+		"paint.Color": {"Matte = 1", "Gloss = 2"},
+	} {
+		if enum := lookup(docs, "definitions", model, "enum"); !reflect.DeepEqual(enum, want) {
+			t.Errorf("the values of %s are %v, want %v", model, enum, want)
+		}
+	}
+}
+
+func TestDefaultTag(t *testing.T) {
+	old := config.Conf.Swagger.DefaultTag
+	t.Cleanup(func() { config.Conf.Swagger.DefaultTag = old })
+	config.Conf.Swagger.DefaultTag = "default"
+	docs := generateFixture(t, "roottag")
+	for path, tag := range map[string]string{"/ping": "default", "/user/": "user"} {
+		if tags := lookup(docs, "paths", path, "get", "tags"); !reflect.DeepEqual(tags, []interface{}{tag}) {
+			t.Errorf("the tags of GET %s are %v, want [%s]", path, tags, tag)
+		}
+	}
+	tags := make(map[interface{}]interface{})
+	for _, tag := range lookup(docs, "tags").([]interface{}) {
+		tags[lookup(tag, "name")] = lookup(tag, "description")
+	}
+	want := map[interface{}]interface{}{"default": "Health of the service\n", "user": "Operations about users\n"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("the tags are %v, want %v", tags, want)
+	}
+}
+
+func TestGetParams(t *testing.T) {
+	for str, want := range map[string][]string{
+		`id path int true "the id"`:                     {"id", "path", "int", "true", "the id"},
+		"id\tpath\tint\ttrue\t\"the id\"":               {"id", "path", "int", "true", "the id"},
+		"id \t path\t \tint true":                       {"id", "path", "int", "true"},
+		"status\tquery\tstring\tfalse\t\"the\tstatus\"": {"status", "query", "string", "false", "the\tstatus"},
+	} {
+		if got := getparams(str); !reflect.DeepEqual(got, want) {
+			t.Errorf("getparams(%q) = %q, want %q", str, got, want)
+		}
+	}
+}
+
+func TestTabSeparatedAnnotations(t *testing.T) {
+	docs := generateFixture(t, "tabs")
+	op := lookup(docs, "paths", "/user/", "get")
+	if op == nil {
+		t.Fatalf("the tab separated @router is not documented: %v", lookup(docs, "paths"))
+	}
+	if id := lookup(op, "operationId"); id != "UserController.ListUsers" {
+		t.Errorf("the operationId is %v", id)
+	}
+	status := lookup(op, "parameters", "0")
+	want := map[string]interface{}{
+		"name":            "status",
+		"in":              "query",
+		"type":            "string",
+		"allowEmptyValue": true,
+		// a tab between quotes belongs to the description
+		"description": "\nthe\tstatus of the users",
+		"default":     "active",
+		"enum":        []interface{}{"active", "archived"},
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("the status param is %v, want %v", status, want)
+	}
+	if name, typ := lookup(op, "parameters", "1", "name"), lookup(op, "parameters", "1", "type"); name != "limit" || typ != "integer" {
+		t.Errorf("the param separated by spaces and tabs is the %v %v", typ, name)
+	}
+}
+
+func TestConstNamespacePaths(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "constpath")
+	if base := lookup(docs, "basePath"); base != "/v1" {
+		t.Errorf("the basePath is %v", base)
+	}
+	for path, id := range map[string]string{
+		"/user/":        "UserController.GetAll",
+		"/admin/store/": "StoreController.GetAll",
+	} {
+		if got := lookup(docs, "paths", path, "get", "operationId"); got != id {
+			t.Errorf("the operation of %s is %v, want %s", path, got, id)
+		}
+	}
+	if n := len(lookup(docs, "paths").(map[string]interface{})); n != 2 {
+		t.Errorf("%d paths are documented, want 2: %v", n, lookup(docs, "paths"))
+	}
+	if !strings.Contains(log.String(), `Cannot resolve the path of NSNamespace at os.Getenv("DYNAMIC_PATH"), it is skipped`) {
+		t.Errorf("the unresolved path is not warned about: %s", log)
+	}
+}
+
+func TestStringValue(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+		ok   bool
+	}{
+		{`"/user"`, "/user", true},
+		{"`/user`", "/user", true},
+		{`("/v" + "1") + "/user"`, "/v1/user", true},
+		{`prefix + "/user"`, "", false},
+		{`path.Join("/v1", "user")`, "", false},
+		{`42`, "", false},
+	}
+	for _, tt := range tests {
+		expr, err := parser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := stringValue(expr); ok != tt.ok || ok && got != tt.want {
+			t.Errorf("stringValue(%s) = %q, %v, want %q, %v", tt.expr, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMapsOfEnums(t *testing.T) {
+	docs := generateFixture(t, "maps")
+	want := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"$ref": "#/definitions/models.Status"},
+	}
+	for name, schema := range map[string]interface{}{
+		"the field":      lookup(docs, "definitions", "models.User", "properties", "Statuses"),
+		"the response":   lookup(docs, "paths", "/user/statuses", "get", "responses", "200", "schema"),
+		"the body param": lookup(docs, "paths", "/user/statuses", "put", "parameters", "0", "schema"),
+	} {
+		if !reflect.DeepEqual(schema, want) {
+			t.Errorf("%s is %v, want %v", name, schema, want)
+		}
+	}
+	status := lookup(docs, "definitions", "models.Status")
+	if typ := lookup(status, "type"); typ != "string" {
+		t.Errorf("the type of models.Status is %v", typ)
+	}
+	enum := []interface{}{`Active = "active"`, `Archived = "archived"`}
+	if got := lookup(status, "enum"); !reflect.DeepEqual(got, enum) {
+		t.Errorf("the enum of models.Status is %v, want %v", got, enum)
+	}
+}
+
+func TestMapValueType(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"map[string]models.Status", "models.Status", true},
+		{"map[string][]int", "[]int", true},
+		{"map[[2]int]map[string]bool", "map[string]bool", true},
+		{"map[string]", "", false},
+		{"models.Status", "", false},
+	}
+	for _, tt := range tests {
+		if value, ok := mapValueType(tt.name); value != tt.value || ok != tt.ok {
+			t.Errorf("mapValueType(%s) = %q, %v, want %q, %v", tt.name, value, ok, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestExplicitOperationID(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "operationid")
+	tests := []struct {
+		path, method, id string
+	}{
+		{"/user/", "get", "listUsers"},
+		// @OperationId wins whether it comes before or after @Title
+		{"/user/{id}", "get", "getUser"},
+		{"/user/{id}", "delete", "UserController.Delete"},
+		{"/user/{id}/remove", "post", "getUser"},
+	}
+	for _, tt := range tests {
+		if id := lookup(docs, "paths", tt.path, tt.method, "operationId"); id != tt.id {
+			t.Errorf("the operationId of %s %s is %v, want %s", tt.method, tt.path, id, tt.id)
+		}
+	}
+	if !strings.Contains(log.String(), "[UserController.Remove] operationId getUser is already used by fixtures/operationid/controllers:UserController.Get") {
+		t.Errorf("the duplicate operationId is not warned about: %s", log)
+	}
+}
+
+func TestAllowEmptyValue(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "allowempty")
+	want := map[string]interface{}{
+		"id":     nil,
+		"org":    nil,
+		"q":      true,
+		"status": nil,
+		"name":   true,
+		"limit":  nil,
+	}
+	params := lookup(docs, "paths", "/user/{org}/{id}", "get", "parameters").([]interface{})
+	if len(params) != len(want) {
+		t.Fatalf("the params are %v", params)
+	}
+	for _, p := range params {
+		name := lookup(p, "name").(string)
+		if allow := lookup(p, "allowEmptyValue"); allow != want[name] {
+			t.Errorf("the allowEmptyValue of %s is %v, want %v", name, allow, want[name])
+		}
+	}
+	for _, warning := range []string{
+		"[UserController.Get] allowEmptyValue is only allowed for query and formData params: org",
+		"[UserController.Get] Invalid allowEmptyValue for param limit: maybe",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("%q is not logged: %s", warning, log)
+		}
+	}
+}
+
+func TestMaxDefinitions(t *testing.T) {
+	old := config.Conf.Swagger.MaxDefinitions
+	t.Cleanup(func() { config.Conf.Swagger.MaxDefinitions = old })
+
+	config.Conf.Swagger.MaxDefinitions = 4
+	log := captureLog(t)
+	generateFixture(t, "manydefs")
+	if strings.Contains(log.String(), "definitions, more than") {
+		t.Errorf("4 definitions are warned about: %s", log)
+	}
+
+	config.Conf.Swagger.MaxDefinitions = 2
+	log.Reset()
+	generateFixture(t, "manydefs")
+	want := "The docs have 4 definitions, more than 2, the packages contributing the most to them are models (3), shared (1)"
+	if !strings.Contains(log.String(), want) {
+		t.Errorf("%q is not logged: %s", want, log)
+	}
+}
+
+func TestScalarResponses(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "scalarresp")
+	tests := []struct {
+		path   string
+		desc   string
+		schema map[string]interface{}
+	}{
+		// the quotes of a description are kept, as for the other responses
+		{"/clock/now", `"timestamp"`, map[string]interface{}{"type": "string", "format": "date-time"}},
+		{"/clock/started", "the start", map[string]interface{}{"type": "string", "format": "date-time", "example": "2026-01-02T03:04:05Z"}},
+		{"/clock/ticks", "the ticks since the start", map[string]interface{}{"type": "integer", "format": "int64", "example": 42.0}},
+		{"/clock/zone", "the offset of the zone", map[string]interface{}{"type": "integer", "format": "int32"}},
+	}
+	for _, tt := range tests {
+		rs := lookup(docs, "paths", tt.path, "get", "responses", "200")
+		if desc := lookup(rs, "description"); desc != tt.desc {
+			t.Errorf("the description of %s is %q, want %q", tt.path, desc, tt.desc)
+		}
+		if schema := lookup(rs, "schema"); !reflect.DeepEqual(schema, tt.schema) {
+			t.Errorf("the schema of %s is %v, want %v", tt.path, schema, tt.schema)
+		}
+	}
+	if want := "[ClockController.Zone] The example east of response 200 is not a valid integer"; !strings.Contains(log.String(), want) {
+		t.Errorf("%q is not logged: %s", want, log)
+	}
+}
+
+func TestParseTagContentTypes(t *testing.T) {
+	defaults := make(map[string][]string)
+	if err := parseTagContentTypes(defaults, "files application/octet-stream,json"); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"application/octet-stream", "application/json"}; !reflect.DeepEqual(defaults["files"], want) {
+		t.Errorf("the media types of files are %v, want %v", defaults["files"], want)
+	}
+	for _, value := range []string{"files", "files yaml"} {
+		if err := parseTagContentTypes(defaults, value); err == nil {
+			t.Errorf("%q is parsed", value)
+		}
+	}
+}
+
+func TestNumericEnumExamples(t *testing.T) {
+	docs := generateFixture(t, "enums")
+	for name, want := range map[string]float64{
+		"models.Level":    1,
+		"models.Priority": 10,
+		// a rune is the number of its code point
+		"models.Grade": 97,
+		"models.Ratio": 0.5,
+	} {
+		// decoded from JSON, a number is a float64 while a string stays a string
+		if example, ok := lookup(docs, "definitions", name, "example").(float64); !ok || example != want {
+			t.Errorf("the example of %s is %#v, want the number %v", name, lookup(docs, "definitions", name, "example"), want)
+		}
+	}
+}
+
+func TestResponseSchemaOverrides(t *testing.T) {
+	docs := generateFixture(t, "respschema")
+	owner := lookup(docs, "paths", "/account/{id}/owner", "get", "responses", "200")
+	if desc := lookup(owner, "description"); desc != `"the owner"` {
+		t.Errorf("the description of the owner response is %v", desc)
+	}
+	want := map[string]interface{}{
+		"allOf":       []interface{}{map[string]interface{}{"$ref": "#/definitions/models.User"}},
+		"title":       "Owner",
+		"description": "The owner of the account",
+	}
+	if schema := lookup(owner, "schema"); !reflect.DeepEqual(schema, want) {
+		t.Errorf("the schema of the owner is %v, want %v", schema, want)
+	}
+	member := lookup(docs, "paths", "/account/members/{id}", "get", "responses", "200", "schema")
+	if !reflect.DeepEqual(member, map[string]interface{}{"$ref": "#/definitions/models.User"}) {
+		t.Errorf("the schema of the member is %v", member)
+	}
+	user := lookup(docs, "definitions", "models.User")
+	if title, desc := lookup(user, "title"), lookup(user, "description"); title != "User" || desc != nil {
+		t.Errorf("the shared definition has the title %v and the description %v", title, desc)
+	}
+}
+
+func TestResponseOptions(t *testing.T) {
+	tests := []struct {
+		desc    string
+		rest    string
+		options map[string]string
+	}{
+		{`"the owner" title=Owner description="The owner of the account"`, `"the owner"`,
+			map[string]string{"title": "Owner", "description": "The owner of the account"}},
+		{`timestamp example:2026-01-02T15:04:05Z`, "timestamp", map[string]string{"example": "2026-01-02T15:04:05Z"}},
+		{`the ratio a=b`, "the ratio a=b", map[string]string{}},
+		{`the name summary=short`, "the name summary=short", map[string]string{}},
+	}
+	for _, tt := range tests {
+		options := make(map[string]string)
+		if rest := responseOptions(tt.desc, options); rest != tt.rest || !reflect.DeepEqual(options, tt.options) {
+			t.Errorf("responseOptions(%s) = %q, %v, want %q, %v", tt.desc, rest, options, tt.rest, tt.options)
+		}
+	}
+}
+
+func TestDeprecatedControllers(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "deprecatedctl")
+	tests := []struct {
+		path, method string
+		deprecated   interface{}
+	}{
+		{"/legacy/", "get", true},
+		{"/legacy/{id}", "get", true},
+		// @Deprecated false of a method wins over the one of its controller
+		{"/legacy/export", "get", nil},
+		{"/user/", "get", nil},
+		{"/user/{id}", "delete", true},
+		{"/user/{id}", "put", nil},
+	}
+	for _, tt := range tests {
+		if deprecated := lookup(docs, "paths", tt.path, tt.method, "deprecated"); deprecated != tt.deprecated {
+			t.Errorf("%s %s is deprecated: %v, want %v", tt.method, tt.path, deprecated, tt.deprecated)
+		}
+	}
+	// the annotation is not a part of the description of the tag
+	if desc := lookup(docs, "tags", "0", "description"); desc != "Operations about the legacy users, use /user instead\n" {
+		t.Errorf("the description of the legacy tag is %q", desc)
+	}
+	if want := "[UserController.Update] Invalid @Deprecated: soon"; !strings.Contains(log.String(), want) {
+		t.Errorf("%q is not logged: %s", want, log)
+	}
+}
+
+func TestMultipleRouters(t *testing.T) {
+	docs := generateFixture(t, "multiroute")
+	tests := []struct {
+		path, method, id string
+	}{
+		{"/user/{id}", "get", "UserController.GetUser"},
+		{"/user/by-id/{id}", "get", "UserController.GetUser_2"},
+		{"/user/by-id/{id}", "head", "UserController.GetUser_2"},
+		{"/user/me", "get", "UserController.GetMe"},
+		{"/user/self", "get", "UserController.GetMe_2"},
+	}
+	for _, tt := range tests {
+		op := lookup(docs, "paths", tt.path, tt.method)
+		if op == nil {
+			t.Errorf("%s %s is not documented", tt.method, tt.path)
+			continue
+		}
+		if id := lookup(op, "operationId"); id != tt.id {
+			t.Errorf("the operationId of %s %s is %v, want %s", tt.method, tt.path, id, tt.id)
+		}
+		if n := len(lookup(op, "parameters").([]interface{})); n != 1 {
+			t.Errorf("%s %s has %d params, want 1", tt.method, tt.path, n)
+		}
+	}
+	if in := lookup(docs, "paths", "/user/by-id/{id}", "get", "parameters", "0", "in"); in != "path" {
+		t.Errorf("the id of the second path is in %v", in)
+	}
+	// the function params are documented under every path
+	if name := lookup(docs, "paths", "/user/self", "get", "parameters", "0", "name"); name != "lang" {
+		t.Errorf("the param of the second path is %v", name)
+	}
+}
+
+func TestTagGroups(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "taggroups")
+	want := []interface{}{
+		map[string]interface{}{"name": "Shop", "tags": []interface{}{"user", "order"}},
+		map[string]interface{}{"name": "Back office", "tags": []interface{}{"admin"}},
+	}
+	if groups := lookup(docs, "x-tagGroups"); !reflect.DeepEqual(groups, want) {
+		t.Errorf("the tag groups are %v, want %v", groups, want)
+	}
+	for _, warning := range []string{
+		"@TagGroup Shop references the unknown tag cart",
+		"@TagGroup Back office references the unknown tag report",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("%q is not logged: %s", warning, log)
+		}
+	}
+	if err := parseTagGroup("Shop"); err == nil {
+		t.Error("a tag group without tags is parsed")
+	}
+}
+
+func TestBuiltinTypes(t *testing.T) {
+	docs := generateFixture(t, "types")
+	int32Type := map[string]interface{}{"type": "integer", "format": "int32"}
+	int64Type := map[string]interface{}{"type": "integer", "format": "int64"}
+	uuidType := map[string]interface{}{"type": "string", "format": "uuid"}
+	properties := lookup(docs, "definitions", "models.Event", "properties")
+	for _, tc := range []struct {
+		field string
+		want  map[string]interface{}
+	}{
+		{"Timeout", int64Type},
+		{"Deadline", int64Type},
+		{"Month", int32Type},
+		{"Weekday", int32Type},
+		{"Retries", map[string]interface{}{"type": "array", "items": int64Type}},
+		{"ID", uuidType},
+		{"Related", map[string]interface{}{"type": "array", "items": uuidType}},
+	} {
+		if got := lookup(properties, tc.field); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s is %v, want %v", tc.field, got, tc.want)
+		}
+	}
+	if n := len(lookup(docs, "definitions").(map[string]interface{})); n != 1 {
+		t.Errorf("the builtin types are documented as definitions: %v", lookup(docs, "definitions"))
+	}
+	param := lookup(docs, "paths", "/event/{id}", "get", "parameters", "1")
+	if typ, format := lookup(param, "type"), lookup(param, "format"); typ != "integer" || format != "int64" {
+		t.Errorf("the timeout param is %v", param)
+	}
+}
+
+func TestTypesFile(t *testing.T) {
+	docs := generateFixture(t, "types")
+	properties := lookup(docs, "definitions", "models.Event", "properties")
+	for field, want := range map[string]interface{}{
+		"Price": map[string]interface{}{"type": "number", "format": "double"},
+		"Note":  map[string]interface{}{"type": "string"},
+	} {
+		if got := lookup(properties, field); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s is %v, want %v", field, got, want)
+		}
+	}
+
+	// the types of a project are forgotten by the next one
+	generateFixture(t, "ignore")
+	if _, ok := basicTypes["decimal.Decimal"]; ok {
+		t.Error("decimal.Decimal is still a basic type")
+	}
+	if _, ok := stdlibObject["&{sql NullString}"]; ok {
+		t.Error("sql.NullString is still a basic type")
+	}
+
+	for _, tc := range []struct {
+		types, err string
+	}{
+		{"Decimal: number", "Decimal should be a type qualified by its package"},
+		{"decimal.Decimal: float", "unknown type float of decimal.Decimal"},
+	} {
+		dir := t.TempDir()
+		if err := ioutil.WriteFile(filepath.Join(dir, swaggerTypesFile), []byte(tc.types), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := loadTypes(dir); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("the error of %q is %v, want %q", tc.types, err, tc.err)
+		}
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "constraints")
+	order := lookup(docs, "definitions", "models.Order")
+	for _, tc := range []struct {
+		name, key string
+		want      interface{}
+	}{
+		{"count", "minimum", 1.0},
+		{"count", "maximum", 5.0},
+		{"nick", "minLength", 3.0},
+		{"nick", "maxLength", 10.0},
+		{"nick", "minimum", nil},
+		// len is both bounds
+		{"pin", "minLength", 4.0},
+		{"pin", "maxLength", 4.0},
+		{"phones", "minItems", 1.0},
+		{"phones", "maxItems", 3.0},
+		{"gift", "maximum", nil},
+	} {
+		if got := lookup(order, "properties", tc.name, tc.key); got != tc.want {
+			t.Errorf("the %s of %s is %v, want %v", tc.key, tc.name, got, tc.want)
+		}
+	}
+	if required := lookup(order, "required"); !reflect.DeepEqual(required, []interface{}{"count", "nick"}) {
+		t.Errorf("the required fields are %v, want [count nick]", required)
+	}
+	if warning := "max is only allowed for numeric, string or array fields: Order.gift"; !strings.Contains(log.String(), warning) {
+		t.Errorf("no warning %q in %q", warning, log.String())
+	}
+}
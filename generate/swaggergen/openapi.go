@@ -0,0 +1,542 @@
+// Copyright 2013 bee authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package swaggergen
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/beego/bee/generate/swaggergen/swagger"
+	beeLogger "github.com/beego/bee/logger"
+)
+
+// openAPIVersion is the version of the OpenAPI 3.0 documents
+const openAPIVersion = "3.0.3"
+
+// openAPIFlows names the OAuth2 flows of OpenAPI 3.0 after the Swagger 2.0 ones
+var openAPIFlows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// componentNameRegex matches the characters which are not allowed in the names of components,
+// such as the brackets of the instantiated generic types
+var componentNameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// openAPIDocs converts rootapi into an OpenAPI 3.0 document. The references to definitions are
+// kept as they are, so that the document can be bundled, withComponents moves them afterwards.
+func openAPIDocs() (map[string]interface{}, error) {
+	value, err := jsonValue(rootapi)
+	if err != nil {
+		return nil, err
+	}
+	docs := value.(map[string]interface{})
+	delete(docs, "swagger")
+	docs["openapi"] = openAPIVersion
+	for _, key := range []string{"host", "basePath", "schemes", "consumes", "produces", "securityDefinitions"} {
+		delete(docs, key)
+	}
+	if servers := rootServers(); len(servers) > 0 {
+		if docs["servers"], err = jsonValue(servers); err != nil {
+			return nil, err
+		}
+	}
+
+	paths := make(map[string]interface{}, len(rootapi.Paths))
+	for rt, item := range rootapi.Paths {
+		ops := make(map[string]interface{})
+		for method, op := range map[string]*swagger.Operation{
+			"get": item.Get, "put": item.Put, "post": item.Post, "delete": item.Delete,
+			"options": item.Options, "head": item.Head, "patch": item.Patch,
+		} {
+			if op != nil {
+				if ops[method], err = openAPIOperation(op); err != nil {
+					return nil, err
+				}
+			}
+		}
+		paths[rt] = ops
+	}
+	docs["paths"] = paths
+
+	if len(rootapi.SecurityDefinitions) > 0 {
+		schemes := make(map[string]interface{}, len(rootapi.SecurityDefinitions))
+		for name, s := range rootapi.SecurityDefinitions {
+			schemes[name] = securityScheme(s)
+		}
+		docs["components"] = map[string]interface{}{"securitySchemes": schemes}
+	}
+	return docs, nil
+}
+
+// rootServers returns the server of the API, made of its host, base path and schemes
+func rootServers() []swagger.Server {
+	return serversAt(rootapi.BasePath)
+}
+
+// serversAt returns the servers of the API host serving basePath. Without any scheme the URL
+// is relative to the scheme the document is served with.
+func serversAt(basePath string) (servers []swagger.Server) {
+	if rootapi.Host == "" {
+		if basePath != "" {
+			servers = append(servers, swagger.Server{URL: basePath})
+		}
+		return
+	}
+	if len(rootapi.Schemes) == 0 {
+		return []swagger.Server{{URL: "//" + rootapi.Host + basePath}}
+	}
+	for _, scheme := range rootapi.Schemes {
+		servers = append(servers, swagger.Server{URL: scheme + "://" + rootapi.Host + basePath})
+	}
+	return
+}
+
+// openAPIOperation converts an operation, whose body and formData params become its request body
+// and whose media types become the content of the request body and responses
+func openAPIOperation(op *swagger.Operation) (map[string]interface{}, error) {
+	value, err := jsonValue(op)
+	if err != nil {
+		return nil, err
+	}
+	out := value.(map[string]interface{})
+	for _, key := range []string{"consumes", "produces", "schemes", "parameters", "responses", "x-parts", "x-callbacks", "x-servers"} {
+		delete(out, key)
+	}
+	// the media types of the responses are their own
+	consumes, produces := op.Consumes, op.DeclaredProduces
+	if len(consumes) == 0 {
+		consumes = rootapi.Consumes
+	}
+	if len(produces) == 0 {
+		produces = rootapi.Produces
+	}
+
+	// the binary contents are as large as the body at most
+	maxBodySize, hasMaxBodySize := op.Extensions["x-max-body-size"]
+
+	var params []interface{}
+	var body map[string]interface{}
+	form := map[string]interface{}{"type": astTypeObject}
+	formProperties := make(map[string]interface{})
+	var formRequired []string
+	hasFile, hasFormData := false, false
+	for _, p := range op.Parameters {
+		hasFormData = hasFormData || p.In == "formData"
+	}
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "body":
+			schema, err := jsonValue(p.Schema)
+			if err != nil {
+				return nil, err
+			}
+			if hasMaxBodySize && p.Schema != nil && p.Schema.Format == "binary" {
+				schema.(map[string]interface{})["maxLength"] = maxBodySize
+			}
+			// an object body may be sent as a form too, unless the form is made of formData params
+			types := mediaTypes(consumes, func(ct string) bool { return !(isFormType(ct) && hasFormData) && ct != amixed })
+			if len(types) == 0 {
+				types = []string{ajson}
+				if p.Schema != nil && p.Schema.Format == "binary" {
+					types = []string{aoctet}
+				}
+			}
+			body = map[string]interface{}{"content": openAPIContent(types, schema, p.Example)}
+			if p.Description != "" {
+				body["description"] = p.Description
+			}
+			if p.Required {
+				body["required"] = true
+			}
+		case "formData":
+			schema, err := paramSchema(p)
+			if err != nil {
+				return nil, err
+			}
+			if p.Description != "" {
+				schema["description"] = p.Description
+			}
+			if hasMaxBodySize && p.Type == "file" {
+				schema["maxLength"] = maxBodySize
+			}
+			formProperties[p.Name] = schema
+			if p.Required {
+				formRequired = append(formRequired, p.Name)
+			}
+			hasFile = hasFile || p.Type == "file"
+		default:
+			param, err := openAPIParameter(p)
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+		}
+	}
+	if len(params) > 0 {
+		out["parameters"] = params
+	}
+	if len(formProperties) > 0 {
+		form["properties"] = formProperties
+		if len(formRequired) > 0 {
+			form["required"] = formRequired
+		}
+		types := mediaTypes(consumes, isFormType)
+		if len(types) == 0 {
+			// files can only be uploaded within multipart forms
+			types = []string{aurl}
+			if hasFile {
+				types = []string{aform}
+			}
+		}
+		if body == nil {
+			body = map[string]interface{}{"content": make(map[string]interface{})}
+		}
+		for ct, media := range openAPIContent(types, form, nil) {
+			body["content"].(map[string]interface{})[ct] = media
+		}
+		if len(formRequired) > 0 {
+			body["required"] = true
+		}
+	}
+	if len(op.Parts) > 0 {
+		properties := make(map[string]interface{}, len(op.Parts))
+		encoding := make(map[string]interface{}, len(op.Parts))
+		for _, part := range op.Parts {
+			value, err := jsonValue(part.Schema)
+			if err != nil {
+				return nil, err
+			}
+			schema := value.(map[string]interface{})
+			if part.Description != "" && part.Schema.Ref == "" {
+				schema["description"] = part.Description
+			}
+			properties[part.Name] = schema
+			encoding[part.Name] = map[string]interface{}{"contentType": part.ContentType}
+		}
+		if body == nil {
+			body = map[string]interface{}{"content": make(map[string]interface{})}
+		}
+		body["content"].(map[string]interface{})[amixed] = map[string]interface{}{
+			"schema":   map[string]interface{}{"type": astTypeObject, "properties": properties},
+			"encoding": encoding,
+		}
+	}
+	if body != nil {
+		out["requestBody"] = body
+	}
+
+	responses := make(map[string]interface{}, len(op.Responses))
+	for code, rs := range op.Responses {
+		response, err := openAPIResponse(rs, produces)
+		if err != nil {
+			return nil, err
+		}
+		if sunset, ok := op.Extensions["x-sunset"].(string); ok {
+			// the responses of an operation going away tell when, as the Sunset header of RFC 8594
+			headers, _ := response["headers"].(map[string]interface{})
+			if headers == nil {
+				headers = make(map[string]interface{})
+				response["headers"] = headers
+			}
+			headers["Sunset"] = map[string]interface{}{
+				"description": "the operation is removed after " + sunset,
+				"schema":      map[string]interface{}{"type": "string"},
+			}
+		}
+		responses[code] = response
+	}
+	out["responses"] = responses
+
+	if len(op.Callbacks) > 0 {
+		callbacks := make(map[string]interface{})
+		for _, cb := range op.Callbacks {
+			schema, err := jsonValue(cb.Schema)
+			if err != nil {
+				return nil, err
+			}
+			request := map[string]interface{}{"content": openAPIContent([]string{ajson}, schema, nil)}
+			if cb.Description != "" {
+				request["description"] = cb.Description
+			}
+			if _, ok := callbacks[cb.Name]; !ok {
+				callbacks[cb.Name] = make(map[string]interface{})
+			}
+			expressions := callbacks[cb.Name].(map[string]interface{})
+			if _, ok := expressions[cb.Expression]; !ok {
+				expressions[cb.Expression] = make(map[string]interface{})
+			}
+			expressions[cb.Expression].(map[string]interface{})[cb.Method] = map[string]interface{}{
+				"requestBody": request,
+				"responses":   map[string]interface{}{"200": map[string]interface{}{"description": "OK"}},
+			}
+		}
+		out["callbacks"] = callbacks
+	}
+	if len(op.Servers) > 0 {
+		if out["servers"], err = jsonValue(op.Servers); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// openAPIParameter converts a query, header or path param, whose type becomes its schema
+func openAPIParameter(p swagger.Parameter) (map[string]interface{}, error) {
+	schema, err := paramSchema(p)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{
+		"name":   p.Name,
+		"in":     p.In,
+		"schema": schema,
+	}
+	if p.Description != "" {
+		out["description"] = p.Description
+	}
+	if p.Required {
+		out["required"] = true
+	}
+	if p.AllowEmptyValue && p.In == "query" {
+		out["allowEmptyValue"] = true
+	}
+	if p.Example != nil {
+		out["example"] = p.Example
+	}
+	switch p.CollectionFormat {
+	case "csv":
+		if p.In == "query" {
+			out["style"], out["explode"] = "form", false
+		}
+	case "ssv":
+		out["style"], out["explode"] = "spaceDelimited", false
+	case "pipes":
+		out["style"], out["explode"] = "pipeDelimited", false
+	case "multi":
+		out["style"], out["explode"] = "form", true
+	case "tsv":
+		// OpenAPI 3.0 has no style for tab-separated values, the param keeps the default one
+		beeLogger.Log.Warnf("The tab-separated values of the %s param %s are documented with the default style", p.In, p.Name)
+	}
+	return out, nil
+}
+
+// paramSchema returns the schema of a param which is not in body, made of its type, format,
+// items, default and enum
+func paramSchema(p swagger.Parameter) (map[string]interface{}, error) {
+	if p.Schema != nil {
+		value, err := jsonValue(p.Schema)
+		if err != nil {
+			return nil, err
+		}
+		return value.(map[string]interface{}), nil
+	}
+	schema := make(map[string]interface{})
+	if p.Type == "file" {
+		schema["type"], schema["format"] = "string", "binary"
+	} else if p.Type != "" {
+		schema["type"] = p.Type
+	}
+	if p.Format != "" {
+		schema["format"] = p.Format
+	}
+	if p.Items != nil {
+		items := map[string]interface{}{"type": p.Items.Type}
+		if p.Items.Format != "" {
+			items["format"] = p.Items.Format
+		}
+		schema["items"] = items
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	return schema, nil
+}
+
+// openAPIResponse converts a response, whose schema is the content of each of its media types,
+// falling back on the ones produced by the operation
+func openAPIResponse(rs swagger.Response, produces []string) (map[string]interface{}, error) {
+	out := map[string]interface{}{"description": rs.Description}
+	if rs.Schema != nil {
+		types := rs.ContentTypes
+		if len(types) == 0 {
+			types = produces
+		}
+		if len(types) == 0 {
+			types = []string{ajson}
+		}
+		schema, err := jsonValue(rs.Schema)
+		if err != nil {
+			return nil, err
+		}
+		out["content"] = openAPIContent(types, schema, nil)
+	} else if len(rs.ContentTypes) > 0 {
+		// a response without schema, such as an error page, is still sent as its media types
+		out["content"] = openAPIContent(rs.ContentTypes, nil, nil)
+	}
+	if len(rs.Headers) > 0 {
+		headers := make(map[string]interface{}, len(rs.Headers))
+		for name, h := range rs.Headers {
+			schema, err := paramSchema(swagger.Parameter{Type: h.Type, Format: h.Format})
+			if err != nil {
+				return nil, err
+			}
+			header := map[string]interface{}{"schema": schema}
+			if h.Description != "" {
+				header["description"] = h.Description
+			}
+			headers[name] = header
+		}
+		out["headers"] = headers
+	}
+	if len(rs.Links) > 0 {
+		links, err := jsonValue(rs.Links)
+		if err != nil {
+			return nil, err
+		}
+		out["links"] = links
+	}
+	return out, nil
+}
+
+// openAPIContent returns the content of a request body or response, the same schema, if any,
+// being sent with every media type
+func openAPIContent(types []string, schema interface{}, example interface{}) map[string]interface{} {
+	content := make(map[string]interface{}, len(types))
+	for _, ct := range types {
+		media := make(map[string]interface{})
+		if schema != nil {
+			media["schema"] = schema
+		}
+		if example != nil {
+			media["example"] = example
+		}
+		content[ct] = media
+	}
+	return content
+}
+
+// securityScheme converts a security definition, basic authentication becoming an http scheme
+// and the flow of oauth2 being one of its flows
+func securityScheme(s swagger.Security) map[string]interface{} {
+	out := map[string]interface{}{"type": s.Type}
+	if s.Description != "" {
+		out["description"] = s.Description
+	}
+	switch s.Type {
+	case "basic":
+		out["type"], out["scheme"] = "http", "basic"
+	case "apiKey":
+		out["name"], out["in"] = s.Name, s.In
+	case "oauth2":
+		// the scopes are kept as they are, in their declaration order
+		flow := map[string]interface{}{"scopes": s.Scopes}
+		if s.AuthorizationURL != "" {
+			flow["authorizationUrl"] = s.AuthorizationURL
+		}
+		if s.TokenURL != "" {
+			flow["tokenUrl"] = s.TokenURL
+		}
+		out["flows"] = map[string]interface{}{openAPIFlows[s.Flow]: flow}
+	}
+	return out
+}
+
+// withComponents moves the definitions of an OpenAPI 3.0 document to its components, the
+// references pointing to them and x-nullable becoming nullable
+func withComponents(docs map[string]interface{}) map[string]interface{} {
+	definitions, ok := docs["definitions"]
+	delete(docs, "definitions")
+	docs = openAPISchemas(docs).(map[string]interface{})
+	if ok {
+		components, _ := docs["components"].(map[string]interface{})
+		if components == nil {
+			components = make(map[string]interface{})
+		}
+		schemas := make(map[string]interface{})
+		for name, def := range definitions.(map[string]interface{}) {
+			schemas[componentName(name)] = openAPISchemas(def)
+		}
+		components["schemas"] = schemas
+		docs["components"] = components
+	}
+	return docs
+}
+
+// openAPISchemas returns a copy of v where the Swagger 2.0 keywords of schemas are replaced by
+// their OpenAPI 3.0 counterparts
+func openAPISchemas(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for key, value := range t {
+			if ref, ok := value.(string); ok && key == "$ref" && strings.HasPrefix(ref, "#/definitions/") {
+				value = "#/components/schemas/" + componentName(ref[len("#/definitions/"):])
+			}
+			if key == "x-nullable" {
+				key = "nullable"
+			}
+			out[key] = openAPISchemas(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, value := range t {
+			out[i] = openAPISchemas(value)
+		}
+		return out
+	}
+	return v
+}
+
+// componentName returns the name of the component of a definition, e.g. models.Page_models.User_
+// for models.Page[models.User]
+func componentName(name string) string {
+	return componentNameRegex.ReplaceAllString(name, "_")
+}
+
+// mediaTypes returns the media types keeping those matching keep
+func mediaTypes(types []string, keep func(string) bool) (kept []string) {
+	for _, ct := range types {
+		if keep(ct) {
+			kept = append(kept, ct)
+		}
+	}
+	return
+}
+
+func isFormType(ct string) bool {
+	return ct == aform || ct == aurl
+}
+
+// jsonValue returns v as decoded from its JSON encoding, made of maps, slices and scalars
+func jsonValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,432 @@
+// Copyright 2013 bee authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package swaggergen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNamespaceServers(t *testing.T) {
+	setSpec(t, "3.0")
+	for _, tc := range []struct {
+		fixture     string
+		rootServers interface{}
+		paths       map[string]interface{} // path:servers of its get operation
+	}{
+		{
+			fixture:     "mounts",
+			rootServers: []interface{}{map[string]interface{}{"url": "/v1"}},
+			paths: map[string]interface{}{
+				"/user/":  nil,
+				"/{name}": []interface{}{map[string]interface{}{"url": "/files"}},
+			},
+		},
+		{
+			fixture:     "nestedbase",
+			rootServers: []interface{}{map[string]interface{}{"url": "/api/v1"}},
+			paths: map[string]interface{}{
+				"/user/":           nil,
+				"/v2/files/{name}": []interface{}{map[string]interface{}{"url": "/api"}},
+			},
+		},
+	} {
+		docs := generateFixture(t, tc.fixture)
+		if servers := lookup(docs, "servers"); !reflect.DeepEqual(servers, tc.rootServers) {
+			t.Errorf("%s: the servers are %v, want %v", tc.fixture, servers, tc.rootServers)
+		}
+		paths := lookup(docs, "paths").(map[string]interface{})
+		if len(paths) != len(tc.paths) {
+			t.Errorf("%s: the paths are %v, want %v", tc.fixture, paths, tc.paths)
+		}
+		for path, want := range tc.paths {
+			if lookup(paths, path, "get") == nil {
+				t.Errorf("%s: GET %s is not documented", tc.fixture, path)
+				continue
+			}
+			if servers := lookup(paths, path, "get", "servers"); !reflect.DeepEqual(servers, want) {
+				t.Errorf("%s: the servers of GET %s are %v, want %v", tc.fixture, path, servers, want)
+			}
+		}
+	}
+}
+
+func TestNamespaceOutsideBasePathIn2(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "mounts")
+	if lookup(docs, "paths", "/{name}") != nil {
+		t.Errorf("the operations of /files are documented under the base path %v", lookup(docs, "basePath"))
+	}
+	if !strings.Contains(log.String(), "The namespace /files is outside of the base path /v1") {
+		t.Errorf("no warning about /files in %q", log.String())
+	}
+}
+
+func TestMultipartBody(t *testing.T) {
+	docs := generateFixture(t, "multipart")
+	op := lookup(docs, "paths", "/upload/", "post")
+	if consumes := lookup(op, "consumes"); !reflect.DeepEqual(consumes, []interface{}{"multipart/mixed"}) {
+		t.Errorf("the operation consumes %v, want [multipart/mixed]", consumes)
+	}
+	// Swagger 2.0 has no multipart/mixed bodies, the parts are documented as an extension
+	want := []interface{}{
+		map[string]interface{}{
+			"name":        "metadata",
+			"contentType": "application/json",
+			"schema":      map[string]interface{}{"$ref": "#/definitions/models.UploadMeta"},
+			"description": "the metadata of the file",
+		},
+		map[string]interface{}{
+			"name":        "file",
+			"contentType": "application/octet-stream",
+			"schema":      map[string]interface{}{"type": "string", "format": "binary"},
+		},
+	}
+	if parts := lookup(op, "x-parts"); !reflect.DeepEqual(parts, want) {
+		t.Errorf("the parts are %v, want %v", parts, want)
+	}
+	if lookup(docs, "definitions", "models.UploadMeta") == nil {
+		t.Error("models.UploadMeta is not defined")
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "multipart")
+	op = lookup(docs, "paths", "/upload/", "post")
+	if lookup(op, "x-parts") != nil {
+		t.Error("the parts are still documented as an extension")
+	}
+	mixed := lookup(op, "requestBody", "content", "multipart/mixed")
+	if ref := lookup(mixed, "schema", "properties", "metadata", "$ref"); ref != "#/components/schemas/models.UploadMeta" {
+		t.Errorf("the metadata part refers to %v", ref)
+	}
+	if format := lookup(mixed, "schema", "properties", "file", "format"); format != "binary" {
+		t.Errorf("the file part has the format %v, want binary", format)
+	}
+	for name, ct := range map[string]string{"metadata": "application/json", "file": "application/octet-stream"} {
+		if got := lookup(mixed, "encoding", name, "contentType"); got != ct {
+			t.Errorf("the %s part is encoded as %v, want %s", name, got, ct)
+		}
+	}
+}
+
+func TestCallback(t *testing.T) {
+	docs := generateFixture(t, "callback")
+	op := lookup(docs, "paths", "/subscription/", "post")
+	// Swagger 2.0 has no callbacks, they are documented as an extension
+	want := []interface{}{
+		map[string]interface{}{
+			"name":        "onEvent",
+			"expression":  "{$request.query.callbackUrl}",
+			"method":      "post",
+			"schema":      map[string]interface{}{"$ref": "#/definitions/models.Event"},
+			"description": "sent for every event",
+		},
+	}
+	if callbacks := lookup(op, "x-callbacks"); !reflect.DeepEqual(callbacks, want) {
+		t.Errorf("the callbacks are %v, want %v", callbacks, want)
+	}
+	if lookup(docs, "definitions", "models.Event") == nil {
+		t.Error("models.Event is not defined")
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "callback")
+	op = lookup(docs, "paths", "/subscription/", "post")
+	if lookup(op, "x-callbacks") != nil {
+		t.Error("the callbacks are still documented as an extension")
+	}
+	request := lookup(op, "callbacks", "onEvent", "{$request.query.callbackUrl}", "post", "requestBody")
+	if ref := lookup(request, "content", "application/json", "schema", "$ref"); ref != "#/components/schemas/models.Event" {
+		t.Errorf("the callback sends %v", ref)
+	}
+	if desc := lookup(request, "description"); desc != "sent for every event" {
+		t.Errorf("the callback is described as %v", desc)
+	}
+}
+
+func TestOperationServer(t *testing.T) {
+	want := []interface{}{map[string]interface{}{"url": "https://cdn.example.com/v1", "description": "content delivery network"}}
+	// Swagger 2.0 has no servers per operation, they are documented as an extension
+	docs := generateFixture(t, "opserver")
+	if servers := lookup(docs, "paths", "/asset/{name}", "get", "x-servers"); !reflect.DeepEqual(servers, want) {
+		t.Errorf("the servers are %v, want %v", servers, want)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "opserver")
+	op := lookup(docs, "paths", "/asset/{name}", "get")
+	if lookup(op, "x-servers") != nil {
+		t.Error("the servers are still documented as an extension")
+	}
+	if servers := lookup(op, "servers"); !reflect.DeepEqual(servers, want) {
+		t.Errorf("the servers are %v, want %v", servers, want)
+	}
+	if servers := lookup(docs, "paths", "/asset/", "get", "servers"); servers != nil {
+		t.Errorf("the operation without @Server has the servers %v", servers)
+	}
+	if servers := lookup(docs, "servers"); !reflect.DeepEqual(servers, []interface{}{map[string]interface{}{"url": "https://api.example.com/v1"}}) {
+		t.Errorf("the servers of the API are %v", servers)
+	}
+}
+
+func TestRequestBodyContentTypes(t *testing.T) {
+	docs := generateFixture(t, "consumes")
+	want := []interface{}{"application/json", "application/x-www-form-urlencoded"}
+	if consumes := lookup(docs, "paths", "/user/", "post", "consumes"); !reflect.DeepEqual(consumes, want) {
+		t.Errorf("the operation consumes %v, want %v", consumes, want)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "consumes")
+	content := lookup(docs, "paths", "/user/", "post", "requestBody", "content").(map[string]interface{})
+	if len(content) != len(want) {
+		t.Errorf("the body is sent as %v, want %v", content, want)
+	}
+	for _, ct := range want {
+		if ref := lookup(content, ct.(string), "schema", "$ref"); ref != "#/components/schemas/models.User" {
+			t.Errorf("the %s body refers to %v", ct, ref)
+		}
+	}
+}
+
+func TestResponseLink(t *testing.T) {
+	want := map[string]interface{}{
+		"GetUser": map[string]interface{}{
+			"operationId": "UserController.Get",
+			"parameters":  map[string]interface{}{"id": "$response.body#/id"},
+			"description": "the created user",
+		},
+	}
+	// Swagger 2.0 has no links, they are documented as an extension
+	docs := generateFixture(t, "links")
+	if links := lookup(docs, "paths", "/user/", "post", "responses", "201", "x-links"); !reflect.DeepEqual(links, want) {
+		t.Errorf("the links are %v, want %v", links, want)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "links")
+	response := lookup(docs, "paths", "/user/", "post", "responses", "201")
+	if lookup(response, "x-links") != nil {
+		t.Error("the links are still documented as an extension")
+	}
+	if links := lookup(response, "links"); !reflect.DeepEqual(links, want) {
+		t.Errorf("the links are %v, want %v", links, want)
+	}
+	if id := lookup(docs, "paths", "/user/{id}", "get", "operationId"); id != "UserController.Get" {
+		t.Errorf("the linked operation has the operationId %v", id)
+	}
+}
+
+func TestBinaryBody(t *testing.T) {
+	want := map[string]interface{}{"type": "string", "format": "binary"}
+	docs := generateFixture(t, "binarybody")
+	param := lookup(docs, "paths", "/upload/", "put", "parameters", "0")
+	if in := lookup(param, "in"); in != "body" {
+		t.Errorf("the upload is in %v, want body", in)
+	}
+	if schema := lookup(param, "schema"); !reflect.DeepEqual(schema, want) {
+		t.Errorf("the schema of the upload is %v, want %v", schema, want)
+	}
+	if typ := lookup(param, "type"); typ != nil {
+		t.Errorf("the body param has the type %v", typ)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "binarybody")
+	content := lookup(docs, "paths", "/upload/", "put", "requestBody", "content")
+	if schema := lookup(content, "application/octet-stream", "schema"); !reflect.DeepEqual(schema, want) {
+		t.Errorf("the upload is sent as %v", content)
+	}
+}
+
+func TestSunset(t *testing.T) {
+	log := captureLog(t)
+	docs := generateFixture(t, "sunset")
+	op := lookup(docs, "paths", "/user/", "get")
+	if deprecated := lookup(op, "deprecated"); deprecated != true {
+		t.Errorf("the operation is not deprecated: %v", op)
+	}
+	if sunset := lookup(op, "x-sunset"); sunset != "2027-01-01" {
+		t.Errorf("the sunset is %v, want 2027-01-01", sunset)
+	}
+	if sunset := lookup(docs, "paths", "/user/{id}", "get", "x-sunset"); sunset != nil {
+		t.Errorf("the invalid sunset is documented as %v", sunset)
+	}
+	if !strings.Contains(log.String(), "[UserController.Get] Invalid @Sunset date: soon") {
+		t.Errorf("no warning about the invalid sunset in %q", log.String())
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "sunset")
+	op = lookup(docs, "paths", "/user/", "get")
+	if deprecated := lookup(op, "deprecated"); deprecated != true {
+		t.Errorf("the operation is not deprecated: %v", op)
+	}
+	header := lookup(op, "responses", "200", "headers", "Sunset")
+	if desc, _ := lookup(header, "description").(string); !strings.Contains(desc, "2027-01-01") {
+		t.Errorf("the Sunset header is %v", header)
+	}
+	if header := lookup(docs, "paths", "/user/{id}", "get", "responses", "200", "headers"); header != nil {
+		t.Errorf("the operation without sunset has the headers %v", header)
+	}
+}
+
+func TestNullableContainers(t *testing.T) {
+	for spec, nullable := range map[string]string{"2.0": "x-nullable", "3.0": "nullable"} {
+		setSpec(t, spec)
+		docs := generateFixture(t, "nullable")
+		properties := lookup(docs, "definitions", "models.User", "properties")
+		if spec == "3.0" {
+			properties = lookup(docs, "components", "schemas", "models.User", "properties")
+		}
+		for name, want := range map[string]interface{}{"emails": "array", "labels": "object", "aliases": "array"} {
+			if typ := lookup(properties, name, "type"); typ != want {
+				t.Errorf("%s: %s has the type %v, want %v", spec, name, typ, want)
+			}
+			if got := lookup(properties, name, nullable); (got == true) != (name != "aliases") {
+				t.Errorf("%s: %s has %s %v", spec, name, nullable, got)
+			}
+		}
+		if items := lookup(properties, "emails", "items", "type"); items != "string" {
+			t.Errorf("%s: the emails are made of %v, want string", spec, items)
+		}
+	}
+}
+
+func TestFormDataArray(t *testing.T) {
+	docs := generateFixture(t, "formarray")
+	op := lookup(docs, "paths", "/post/", "post")
+	tags := lookup(op, "parameters", "1")
+	want := map[string]interface{}{
+		"in":               "formData",
+		"name":             "tags",
+		"description":      "\ntags",
+		"type":             "array",
+		"items":            map[string]interface{}{"type": "string"},
+		"collectionFormat": "multi",
+		"allowEmptyValue":  true,
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("the tags param is %v, want %v", tags, want)
+	}
+	if consumes := lookup(op, "consumes"); !reflect.DeepEqual(consumes, []interface{}{"multipart/form-data"}) {
+		t.Errorf("the operation consumes %v, want [multipart/form-data]", consumes)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "formarray")
+	form := lookup(docs, "paths", "/post/", "post", "requestBody", "content", "multipart/form-data", "schema")
+	if items := lookup(form, "properties", "tags", "items", "type"); items != "string" {
+		t.Errorf("the form is %v", form)
+	}
+}
+
+func TestNullableEnums(t *testing.T) {
+	for spec, nullable := range map[string]string{"2.0": "x-nullable", "3.0": "nullable"} {
+		setSpec(t, spec)
+		docs := generateFixture(t, "nullable")
+		definitions, ref := lookup(docs, "definitions"), "#/definitions/models.Status"
+		if spec == "3.0" {
+			definitions, ref = lookup(docs, "components", "schemas"), "#/components/schemas/models.Status"
+		}
+		if enum := lookup(definitions, "models.Status", "enum"); !reflect.DeepEqual(enum, []interface{}{"Active = 0", "Inactive = 1"}) {
+			t.Errorf("%s: the values of models.Status are %v", spec, enum)
+		}
+		properties := lookup(definitions, "models.User", "properties")
+		// the reference to the enum is wrapped, as the siblings of a $ref are ignored
+		status := lookup(properties, "status")
+		if got := lookup(status, "allOf", "0", "$ref"); got != ref || lookup(status, nullable) != true {
+			t.Errorf("%s: the pointer to the enum is documented as %v, want a nullable %s", spec, status, ref)
+		}
+		if state := lookup(properties, "state"); !reflect.DeepEqual(state, map[string]interface{}{"$ref": ref}) {
+			t.Errorf("%s: the enum is documented as %v, want a reference to %s", spec, state, ref)
+		}
+	}
+}
+
+func TestPatchContentTypes(t *testing.T) {
+	patch := map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/definitions/JSONPatchOperation"}}
+	docs := generateFixture(t, "jsonpatch")
+	op := lookup(docs, "paths", "/user/{id}", "patch")
+	if consumes := lookup(op, "consumes"); !reflect.DeepEqual(consumes, []interface{}{"application/json-patch+json"}) {
+		t.Errorf("the JSON Patch operation consumes %v", consumes)
+	}
+	// the body of a JSON Patch is the list of the operations, when not documented
+	if schema := lookup(op, "parameters", "1", "schema"); !reflect.DeepEqual(schema, patch) {
+		t.Errorf("the JSON Patch body is %v, want %v", schema, patch)
+	}
+	if ops := lookup(docs, "definitions", "JSONPatchOperation", "properties", "op", "enum"); len(ops.([]interface{})) != 6 {
+		t.Errorf("the JSON Patch operations are %v", ops)
+	}
+	op = lookup(docs, "paths", "/user/{id}/merge", "patch")
+	if consumes := lookup(op, "consumes"); !reflect.DeepEqual(consumes, []interface{}{"application/merge-patch+json"}) {
+		t.Errorf("the JSON Merge Patch operation consumes %v", consumes)
+	}
+	if ref := lookup(op, "parameters", "1", "schema", "$ref"); ref != "#/definitions/models.User" {
+		t.Errorf("the JSON Merge Patch body refers to %v, want models.User", ref)
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "jsonpatch")
+	content := lookup(docs, "paths", "/user/{id}", "patch", "requestBody", "content")
+	if ref := lookup(content, "application/json-patch+json", "schema", "items", "$ref"); ref != "#/components/schemas/JSONPatchOperation" {
+		t.Errorf("the JSON Patch request body is %v", content)
+	}
+	content = lookup(docs, "paths", "/user/{id}/merge", "patch", "requestBody", "content")
+	if ref := lookup(content, "application/merge-patch+json", "schema", "$ref"); ref != "#/components/schemas/models.User" {
+		t.Errorf("the JSON Merge Patch request body is %v", content)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	const tenMB = 10 << 20
+	log := captureLog(t)
+	docs := generateFixture(t, "maxbody")
+	for _, op := range []interface{}{lookup(docs, "paths", "/upload/", "put"), lookup(docs, "paths", "/upload/form", "post")} {
+		if size := lookup(op, "x-max-body-size"); size != float64(tenMB) {
+			t.Errorf("the max body size of %v is %v", lookup(op, "operationId"), size)
+		}
+	}
+	if size := lookup(docs, "paths", "/upload/{id}", "delete", "x-max-body-size"); size != nil {
+		t.Errorf("the invalid max body size is documented as %v", size)
+	}
+	for _, warning := range []string{
+		"[UploadController.Get] @MaxBodySize is documented for an operation without any body or formData param",
+		"[UploadController.Delete] Invalid @MaxBodySize: 10MB.",
+	} {
+		if !strings.Contains(log.String(), warning) {
+			t.Errorf("%q is not logged: %s", warning, log)
+		}
+	}
+
+	setSpec(t, "3.0")
+	docs = generateFixture(t, "maxbody")
+	body := lookup(docs, "paths", "/upload/", "put", "requestBody", "content", "application/octet-stream", "schema")
+	if size := lookup(body, "maxLength"); size != float64(tenMB) {
+		t.Errorf("the binary body is %v", body)
+	}
+	form := lookup(docs, "paths", "/upload/form", "post", "requestBody", "content", "multipart/form-data", "schema", "properties")
+	if size := lookup(form, "content", "maxLength"); size != float64(tenMB) {
+		t.Errorf("the file of the form is %v", lookup(form, "content"))
+	}
+	// only the binary contents are limited by the size of the body
+	if size := lookup(form, "name", "maxLength"); size != nil {
+		t.Errorf("the name of the form has the max length %v", size)
+	}
+	if size := lookup(docs, "paths", "/upload/", "put", "x-max-body-size"); size != float64(tenMB) {
+		t.Errorf("the max body size of the upload is %v", size)
+	}
+}
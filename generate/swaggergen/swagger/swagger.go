@@ -1,5 +1,13 @@
 package swagger
 
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
 // Swagger list the resource
 type Swagger struct {
 	SwaggerVersion      string                `json:"swagger,omitempty" yaml:"swagger,omitempty"`
@@ -14,6 +22,7 @@ type Swagger struct {
 	SecurityDefinitions map[string]Security   `json:"securityDefinitions,omitempty" yaml:"securityDefinitions,omitempty"`
 	Security            []map[string][]string `json:"security,omitempty" yaml:"security,omitempty"`
 	Tags                []Tag                 `json:"tags,omitempty" yaml:"tags,omitempty"`
+	TagGroups           []TagGroup            `json:"x-tagGroups,omitempty" yaml:"x-tagGroups,omitempty"`
 	ExternalDocs        *ExternalDocs         `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
 }
 
@@ -24,8 +33,15 @@ type Information struct {
 	Version        string `json:"version,omitempty" yaml:"version,omitempty"`
 	TermsOfService string `json:"termsOfService,omitempty" yaml:"termsOfService,omitempty"`
 
-	Contact Contact  `json:"contact,omitempty" yaml:"contact,omitempty"`
-	License *License `json:"license,omitempty" yaml:"license,omitempty"`
+	Contact    Contact    `json:"contact,omitempty" yaml:"contact,omitempty"`
+	License    *License   `json:"license,omitempty" yaml:"license,omitempty"`
+	Extensions Extensions `json:"-" yaml:",inline"`
+}
+
+// MarshalJSON encodes the information along with its extensions.
+func (i Information) MarshalJSON() ([]byte, error) {
+	type information Information
+	return marshalWithExtensions(information(i), i.Extensions)
 }
 
 // Contact information for the exposed API.
@@ -66,21 +82,94 @@ type Operation struct {
 	Responses   map[string]Response   `json:"responses,omitempty" yaml:"responses,omitempty"`
 	Security    []map[string][]string `json:"security,omitempty" yaml:"security,omitempty"`
 	Deprecated  bool                  `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	Extensions  Extensions            `json:"-" yaml:",inline"`
+
+	Servers          []Server   `json:"x-servers,omitempty" yaml:"x-servers,omitempty"`     // The servers of OpenAPI 3.0 documents.
+	Parts            []Part     `json:"x-parts,omitempty" yaml:"x-parts,omitempty"`         // Parts of a multipart/mixed body, the request body of OpenAPI 3.0 documents.
+	Callbacks        []Callback `json:"x-callbacks,omitempty" yaml:"x-callbacks,omitempty"` // The callbacks of OpenAPI 3.0 documents.
+	DeclaredProduces []string   `json:"-" yaml:"-"`                                         // Produces before the media types of the responses are merged into it for Swagger 2.0.
+}
+
+// Callback A request sent by the API to the client, out of band of an operation.
+type Callback struct {
+	Name        string  `json:"name" yaml:"name"`
+	Expression  string  `json:"expression" yaml:"expression"` // Runtime expression of the URL, such as {$request.body#/callbackUrl}.
+	Method      string  `json:"method" yaml:"method"`
+	Schema      *Schema `json:"schema" yaml:"schema"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Part A part of a multipart request body, encoded with its own content type.
+type Part struct {
+	Name        string  `json:"name" yaml:"name"`
+	ContentType string  `json:"contentType" yaml:"contentType"`
+	Schema      *Schema `json:"schema" yaml:"schema"`
+	Description string  `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// Operations returns the operations of the path, in a fixed order.
+func (i *Item) Operations() []*Operation {
+	var ops []*Operation
+	for _, op := range []*Operation{i.Get, i.Put, i.Post, i.Delete, i.Options, i.Head, i.Patch} {
+		if op != nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// Copy returns a copy of the item whose operations can be changed independently of those of i.
+func (i *Item) Copy() *Item {
+	item := *i
+	for _, op := range []**Operation{&item.Get, &item.Put, &item.Post, &item.Delete, &item.Options, &item.Head, &item.Patch} {
+		if *op == nil {
+			continue
+		}
+		o := **op
+		if o.Responses != nil {
+			o.Responses = make(map[string]Response, len((*op).Responses))
+			for code, rs := range (*op).Responses {
+				o.Responses[code] = rs
+			}
+		}
+		*op = &o
+	}
+	return &item
+}
+
+// CodeSample A snippet calling an operation, listed in its x-codeSamples extension.
+type CodeSample struct {
+	Lang   string `json:"lang" yaml:"lang"`
+	Source string `json:"source" yaml:"source"`
+}
+
+// Server An object representing a server of OpenAPI 3.0 documents.
+type Server struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// MarshalJSON encodes the operation along with its extensions.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type operation Operation
+	return marshalWithExtensions(operation(o), o.Extensions)
 }
 
 // Parameter Describes a single operation parameter.
 type Parameter struct {
-	In              string          `json:"in,omitempty" yaml:"in,omitempty"`
-	Name            string          `json:"name,omitempty" yaml:"name,omitempty"`
-	Description     string          `json:"description,omitempty" yaml:"description,omitempty"`
-	Required        bool            `json:"required,omitempty" yaml:"required,omitempty"`
-	Schema          *Schema         `json:"schema,omitempty" yaml:"schema,omitempty"`
-	Type            string          `json:"type,omitempty" yaml:"type,omitempty"`
-	Format          string          `json:"format,omitempty" yaml:"format,omitempty"`
-	Items           *ParameterItems `json:"items,omitempty" yaml:"items,omitempty"`
-	AllowEmptyValue bool            `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
-	Default         interface{}     `json:"default,omitempty" yaml:"default,omitempty"`
-	Enum            []interface{}   `json:"enum,omitempty" yaml:"enum,omitempty"`
+	In               string          `json:"in,omitempty" yaml:"in,omitempty"`
+	Name             string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Description      string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Required         bool            `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema           *Schema         `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Type             string          `json:"type,omitempty" yaml:"type,omitempty"`
+	Format           string          `json:"format,omitempty" yaml:"format,omitempty"`
+	Items            *ParameterItems `json:"items,omitempty" yaml:"items,omitempty"`
+	CollectionFormat string          `json:"collectionFormat,omitempty" yaml:"collectionFormat,omitempty"`
+	AllowEmptyValue  bool            `json:"allowEmptyValue,omitempty" yaml:"allowEmptyValue,omitempty"`
+	Default          interface{}     `json:"default,omitempty" yaml:"default,omitempty"`
+	Enum             []interface{}   `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Example          interface{}     `json:"x-example,omitempty" yaml:"x-example,omitempty"` // Only part of the schema of body params in Swagger 2.0.
 }
 
 // ParameterItems A limited subset of JSON-Schema's items object. It is used by parameter definitions that are not located in "body".
@@ -95,52 +184,133 @@ type ParameterItems struct {
 
 // Schema Object allows the definition of input and output data types.
 type Schema struct {
-	Ref         string               `json:"$ref,omitempty" yaml:"$ref,omitempty"`
-	Title       string               `json:"title,omitempty" yaml:"title,omitempty"`
-	Format      string               `json:"format,omitempty" yaml:"format,omitempty"`
-	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
-	Required    []string             `json:"required,omitempty" yaml:"required,omitempty"`
-	Type        string               `json:"type,omitempty" yaml:"type,omitempty"`
-	Items       *Schema              `json:"items,omitempty" yaml:"items,omitempty"`
-	Properties  map[string]Propertie `json:"properties,omitempty" yaml:"properties,omitempty"`
-	Enum        []interface{}        `json:"enum,omitempty" yaml:"enum,omitempty"`
-	Example     interface{}          `json:"example,omitempty" yaml:"example,omitempty"`
-	AllOf       []*Schema            `json:"allOf,omitempty" yaml:"allOf,omitempty"`
-}
-
-// Propertie are taken from the JSON Schema definition but their definitions were adjusted to the Swagger Specification
-type Propertie struct {
 	Ref                  string               `json:"$ref,omitempty" yaml:"$ref,omitempty"`
 	Title                string               `json:"title,omitempty" yaml:"title,omitempty"`
+	Format               string               `json:"format,omitempty" yaml:"format,omitempty"`
 	Description          string               `json:"description,omitempty" yaml:"description,omitempty"`
-	Default              interface{}          `json:"default,omitempty" yaml:"default,omitempty"`
-	Type                 string               `json:"type,omitempty" yaml:"type,omitempty"`
-	Example              interface{}          `json:"example,omitempty" yaml:"example,omitempty"`
 	Required             []string             `json:"required,omitempty" yaml:"required,omitempty"`
-	Format               string               `json:"format,omitempty" yaml:"format,omitempty"`
-	ReadOnly             bool                 `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	Type                 string               `json:"type,omitempty" yaml:"type,omitempty"`
+	Items                *Schema              `json:"items,omitempty" yaml:"items,omitempty"`
 	Properties           map[string]Propertie `json:"properties,omitempty" yaml:"properties,omitempty"`
-	Items                *Propertie           `json:"items,omitempty" yaml:"items,omitempty"`
-	AdditionalProperties *Propertie           `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	AdditionalProperties *Schema              `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Enum                 []interface{}        `json:"enum,omitempty" yaml:"enum,omitempty"`
+	EnumTruncated        bool                 `json:"x-enum-truncated,omitempty" yaml:"x-enum-truncated,omitempty"`
+	Example              interface{}          `json:"example,omitempty" yaml:"example,omitempty"`
+	AllOf                []*Schema            `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	XML                  *XML                 `json:"xml,omitempty" yaml:"xml,omitempty"`
+}
+
+// Propertie are taken from the JSON Schema definition but their definitions were adjusted to the Swagger Specification
+type Propertie struct {
+	Ref                  string                `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Title                string                `json:"title,omitempty" yaml:"title,omitempty"`
+	Description          string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Default              interface{}           `json:"default,omitempty" yaml:"default,omitempty"`
+	Type                 string                `json:"type,omitempty" yaml:"type,omitempty"`
+	Example              interface{}           `json:"example,omitempty" yaml:"example,omitempty"`
+	Enum                 []interface{}         `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Required             []string              `json:"required,omitempty" yaml:"required,omitempty"`
+	Format               string                `json:"format,omitempty" yaml:"format,omitempty"`
+	ReadOnly             bool                  `json:"readOnly,omitempty" yaml:"readOnly,omitempty"`
+	Nullable             bool                  `json:"x-nullable,omitempty" yaml:"x-nullable,omitempty"`
+	Properties           map[string]Propertie  `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Items                *Propertie            `json:"items,omitempty" yaml:"items,omitempty"`
+	MinItems             *int                  `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	MaxItems             *int                  `json:"maxItems,omitempty" yaml:"maxItems,omitempty"`
+	UniqueItems          bool                  `json:"uniqueItems,omitempty" yaml:"uniqueItems,omitempty"`
+	Minimum              *float64              `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	ExclusiveMinimum     bool                  `json:"exclusiveMinimum,omitempty" yaml:"exclusiveMinimum,omitempty"`
+	Maximum              *float64              `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+	ExclusiveMaximum     bool                  `json:"exclusiveMaximum,omitempty" yaml:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64              `json:"multipleOf,omitempty" yaml:"multipleOf,omitempty"`
+	MinLength            *int                  `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+	MaxLength            *int                  `json:"maxLength,omitempty" yaml:"maxLength,omitempty"`
+	AdditionalProperties *Propertie            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	PatternProperties    map[string]*Propertie `json:"x-patternProperties,omitempty" yaml:"x-patternProperties,omitempty"`
+	KeyType              string                `json:"x-key-type,omitempty" yaml:"x-key-type,omitempty"` // The Go type of the keys of a map, when they are not strings.
+	AllOf                []*Propertie          `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	XML                  *XML                  `json:"xml,omitempty" yaml:"xml,omitempty"`
+}
+
+// XML A metadata object that allows for more fine-tuned XML model definitions.
+type XML struct {
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Prefix    string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Attribute bool   `json:"attribute,omitempty" yaml:"attribute,omitempty"`
+	Wrapped   bool   `json:"wrapped,omitempty" yaml:"wrapped,omitempty"`
 }
 
 // Response as they are returned from executing this operation.
 type Response struct {
-	Description string  `json:"description" yaml:"description"`
-	Schema      *Schema `json:"schema,omitempty" yaml:"schema,omitempty"`
-	Ref         string  `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Description string            `json:"description" yaml:"description"`
+	Schema      *Schema           `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Headers     map[string]Header `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Ref         string            `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+
+	Links        map[string]Link `json:"x-links,omitempty" yaml:"x-links,omitempty"` // Links of OpenAPI 3.0 documents.
+	ContentTypes []string        `json:"-" yaml:"-"`                                 // Media types of this response, merged into the operation produces for Swagger 2.0.
+}
+
+// Header A header sent with a response.
+type Header struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	Format      string `json:"format,omitempty" yaml:"format,omitempty"`
+}
+
+// Link represents a possible design-time link from a response to another operation.
+type Link struct {
+	OperationID string            `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	Parameters  map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Description string            `json:"description,omitempty" yaml:"description,omitempty"`
 }
 
 // Security Allows the definition of a security scheme that can be used by the operations
 type Security struct {
-	Type             string            `json:"type,omitempty" yaml:"type,omitempty"` // Valid values are "basic", "apiKey" or "oauth2".
-	Description      string            `json:"description,omitempty" yaml:"description,omitempty"`
-	Name             string            `json:"name,omitempty" yaml:"name,omitempty"`
-	In               string            `json:"in,omitempty" yaml:"in,omitempty"`     // Valid values are "query" or "header".
-	Flow             string            `json:"flow,omitempty" yaml:"flow,omitempty"` // Valid values are "implicit", "password", "application" or "accessCode".
-	AuthorizationURL string            `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
-	TokenURL         string            `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
-	Scopes           map[string]string `json:"scopes,omitempty" yaml:"scopes,omitempty"` // The available scopes for the OAuth2 security scheme.
+	Type             string `json:"type,omitempty" yaml:"type,omitempty"` // Valid values are "basic", "apiKey" or "oauth2".
+	Description      string `json:"description,omitempty" yaml:"description,omitempty"`
+	Name             string `json:"name,omitempty" yaml:"name,omitempty"`
+	In               string `json:"in,omitempty" yaml:"in,omitempty"`     // Valid values are "query" or "header".
+	Flow             string `json:"flow,omitempty" yaml:"flow,omitempty"` // Valid values are "implicit", "password", "application" or "accessCode".
+	AuthorizationURL string `json:"authorizationUrl,omitempty" yaml:"authorizationUrl,omitempty"`
+	TokenURL         string `json:"tokenUrl,omitempty" yaml:"tokenUrl,omitempty"`
+	Scopes           Scopes `json:"scopes,omitempty" yaml:"scopes,omitempty"` // The available scopes for the OAuth2 security scheme.
+}
+
+// Scope An OAuth2 scope along with its description.
+type Scope struct {
+	Name        string
+	Description string
+}
+
+// Scopes The OAuth2 scopes of a security scheme, encoded as a map keeping their declaration order.
+type Scopes []Scope
+
+// MarshalJSON encodes the scopes as an object whose keys keep the declaration order.
+func (s Scopes) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	for i, scope := range s {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(scope.Name)
+		value, _ := json.Marshal(scope.Description)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML encodes the scopes as a mapping whose keys keep the declaration order.
+func (s Scopes) MarshalYAML() (interface{}, error) {
+	m := make(yaml.MapSlice, 0, len(s))
+	for _, scope := range s {
+		m = append(m, yaml.MapItem{Key: scope.Name, Value: scope.Description})
+	}
+	return m, nil
 }
 
 // Tag Allows adding meta data to a single tag that is used by the Operation Object
@@ -150,8 +320,47 @@ type Tag struct {
 	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty" yaml:"externalDocs,omitempty"`
 }
 
+// TagGroup A section of the navigation of ReDoc, listed in the x-tagGroups extension.
+type TagGroup struct {
+	Name string   `json:"name" yaml:"name"`
+	Tags []string `json:"tags" yaml:"tags"`
+}
+
 // ExternalDocs include Additional external documentation
 type ExternalDocs struct {
 	Description string `json:"description,omitempty" yaml:"description,omitempty"`
 	URL         string `json:"url,omitempty" yaml:"url,omitempty"`
 }
+
+// Extensions holds the specification extensions of an object, their names start with "x-".
+type Extensions map[string]interface{}
+
+// marshalWithExtensions appends the extensions, sorted by name, to the JSON object encoding v.
+func marshalWithExtensions(v interface{}, ext Extensions) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(ext) == 0 {
+		return b, err
+	}
+	names := make([]string, 0, len(ext))
+	for name := range ext {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	for i, name := range names {
+		value, err := json.Marshal(ext[name])
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 || len(b) > 2 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
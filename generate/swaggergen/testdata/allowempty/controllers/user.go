@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Param org path string false "the organization of the user" allowEmptyValue=true
+// @Param q query string false "a search"
+// @Param status query string false "the status" allowEmptyValue=false
+// @Param name query string true "the name" allowEmptyValue=true
+// @Param limit query int false "the limit" allowEmptyValue=maybe
+// @Success 200 {string} the user
+// @router /:org/:id [get]
+func (u *UserController) Get() {
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title DeleteUser
+// @Audit user deletion
+// @FeatureFlag purge
+// @Success 200 {string} deleted
+// @router / [delete]
+func (u *UserController) Delete() {
+}
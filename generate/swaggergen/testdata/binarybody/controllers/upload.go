@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about uploads
+type UploadController struct {
+	beego.Controller
+}
+
+// @Title Upload
+// @Param body body file true "raw upload"
+// @Success 201 {string} the id of the upload
+// @router / [put]
+func (u *UploadController) Put() {
+}
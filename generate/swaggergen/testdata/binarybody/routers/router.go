@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title binary bodies
+package routers
+
+import (
+	"fixtures/binarybody/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/upload",
+			beego.NSInclude(&controllers.UploadController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
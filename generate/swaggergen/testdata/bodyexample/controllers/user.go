@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	_ "fixtures/bodyexample/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title CreateUser
+// @Param body body models.CreateUser true "the user" example:file:examples/create.json
+// @Success 200 {string} created
+// @router / [post]
+func (u *UserController) Post() {
+}
+
+// @Title UpdateUser
+// @Param body body models.CreateUser true "the user" example:file:examples/update.json
+// @Success 200 {string} updated
+// @router / [put]
+func (u *UserController) Put() {
+}
+
+// @Title ImportUsers
+// @Param body body models.CreateUser true "the user" example:file:examples/missing.json
+// @Success 200 {string} imported
+// @router /import [post]
+func (u *UserController) Import() {
+}
@@ -0,0 +1,7 @@
+package models
+
+type CreateUser struct {
+	Name  string `json:"name" required:"true"`
+	Email string `json:"email" required:"true"`
+	Age   int    `json:"age"`
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	_ "fixtures/callback/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Subscriptions to events
+type SubscriptionController struct {
+	beego.Controller
+}
+
+// @Title Subscribe
+// @Param callbackUrl query string true "where the events are sent"
+// @Callback onEvent {$request.query.callbackUrl} post {object} models.Event "sent for every event"
+// @Success 201 {string} the id of the subscription
+// @router / [post]
+func (s *SubscriptionController) Post() {
+}
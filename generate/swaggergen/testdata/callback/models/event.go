@@ -0,0 +1,5 @@
+package models
+
+type Event struct {
+	Kind string `json:"kind"`
+}
@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title callback
+package routers
+
+import (
+	"fixtures/callback/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/subscription",
+			beego.NSInclude(&controllers.SubscriptionController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {string} the user
+// @router /:id [get]
+func (u *UserController) Get() {
+}
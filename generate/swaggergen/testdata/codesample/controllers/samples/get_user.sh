@@ -0,0 +1 @@
+curl -X GET https://api.example.com/v1/user/42
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {string} the user
+// @CodeSample curl samples/get_user.sh
+// @CodeSample go client.GetUser(42)
+// @router /:id [get]
+func (u *UserController) Get() {
+}
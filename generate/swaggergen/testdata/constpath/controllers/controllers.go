@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) GetAll() {
+}
+
+// Operations about the store
+type StoreController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the store
+// @router / [get]
+func (s *StoreController) GetAll() {
+}
+
+// Operations under a path known at runtime
+type DynamicController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the dynamic things
+// @router / [get]
+func (d *DynamicController) GetAll() {
+}
@@ -0,0 +1,4 @@
+package paths
+
+// Admin is the path of the admin namespace
+const Admin = "/admin"
@@ -0,0 +1,32 @@
+// @APIVersion 1.0.0
+// @Title constpath
+package routers
+
+import (
+	"os"
+
+	"fixtures/constpath/controllers"
+	"fixtures/constpath/paths"
+
+	"github.com/astaxie/beego"
+)
+
+const (
+	version   = "/v1"
+	usersPath = "/user"
+)
+
+func init() {
+	ns := beego.NewNamespace(version,
+		beego.NSNamespace(usersPath,
+			beego.NSInclude(&controllers.UserController{}),
+		),
+		beego.NSNamespace(paths.Admin+"/"+"store",
+			beego.NSInclude(&controllers.StoreController{}),
+		),
+		beego.NSNamespace(os.Getenv("DYNAMIC_PATH"),
+			beego.NSInclude(&controllers.DynamicController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	_ "fixtures/constraints/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about orders
+type OrderController struct {
+	beego.Controller
+}
+
+// @Title GetOrder
+// @Success 200 {object} models.Order
+// @router / [get]
+func (c *OrderController) Get() {
+}
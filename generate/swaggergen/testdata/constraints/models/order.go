@@ -0,0 +1,18 @@
+package models
+
+type Order struct {
+	Items []string `json:"items" validate:"minItems=1,maxItems=10,uniqueItems"`
+	Tags  []string `json:"tags" minItems:"0" uniqueItems:"false"`
+	Notes []string `json:"notes" maxItems:"-1"`
+	Name  string   `json:"name" validate:"minItems=1"`
+
+	Price    float64 `json:"price" validate:"multipleOf=0.5,exclusiveMinimum=0,exclusiveMaximum=1000"`
+	Quantity int     `json:"quantity" minimum:"1" maximum:"99" multipleOf:"0"`
+	Code     string  `json:"code" multipleOf:"2"`
+
+	Count  int      `json:"count" validate:"required,min=1,max=5"`
+	Nick   string   `json:"nick" validate:"required,min=3,max=10"`
+	Pin    string   `json:"pin" validate:"len=4"`
+	Phones []string `json:"phones" validate:"min=1,max=3"`
+	Gift   bool     `json:"gift" validate:"max=1"`
+}
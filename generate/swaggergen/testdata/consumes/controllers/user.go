@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	_ "fixtures/consumes/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title CreateUser
+// @Param body body models.User true "the user"
+// @Success 201 {object} models.User
+// @Accept json,urlencoded
+// @router / [post]
+func (u *UserController) Post() {
+}
@@ -0,0 +1,5 @@
+package models
+
+type User struct {
+	Name string `json:"name"`
+}
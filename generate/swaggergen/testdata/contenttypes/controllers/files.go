@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about files
+type FileController struct {
+	beego.Controller
+}
+
+// @Title Download
+// @Param id path string true "the id of the file"
+// @Success 200 {file} the content of the file
+// @router /:id [get]
+func (f *FileController) Download() {
+}
+
+// @Title Upload
+// @Param body body []byte true "the content of the file"
+// @Success 201 {string} the id of the file
+// @router / [post]
+func (f *FileController) Upload() {
+}
+
+// @Title Stat
+// @Param id path string true "the id of the file"
+// @Success 200 {string} the size of the file
+// @Accept json
+// @router /:id/stat [get]
+func (f *FileController) Stat() {
+}
+
+// Operations about reports
+type ReportController struct {
+	beego.Controller
+}
+
+// @Title List
+// @Success 200 {array} string the names of the reports
+// @router / [get]
+func (r *ReportController) List() {
+}
+
+// @Title Create
+// @Param body body string true "the report"
+// @Success 201 {string} the name of the report
+// @router / [post]
+func (r *ReportController) Create() {
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	_ "fixtures/contenttypes/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User
+// @Accept json
+// @router /:id [get]
+func (u *UserController) Get() {
+}
+
+// @Title SearchUsers
+// @Success 200 {array} models.User
+// @Accept urlencoded
+// @router /search [get]
+func (u *UserController) Search() {
+}
+
+// @Title CreateUser
+// @Param body body models.User true "the user"
+// @Success 201 {object} models.User
+// @Accept json
+// @router / [post]
+func (u *UserController) Post() {
+}
+
+// @Title ImportUser
+// @Param body body models.User true "the user"
+// @Success 200 {object} models.User
+// @Success 201 {object} models.User
+// @Failure 500 server error page text/html
+// @router /import [post]
+func (u *UserController) Import() {
+}
+
+// @Title ExportUser
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User "the user" application/json,application/xml
+// @router /:id/export [get]
+func (u *UserController) Export() {
+}
@@ -0,0 +1,27 @@
+// @APIVersion 1.0.0
+// @Title contenttypes
+// @TagProduces files application/octet-stream
+// @TagConsumes files application/octet-stream
+// @TagProduces reports xml,json
+package routers
+
+import (
+	"fixtures/contenttypes/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+		beego.NSNamespace("/files",
+			beego.NSInclude(&controllers.FileController{}),
+		),
+		beego.NSNamespace("/reports",
+			beego.NSInclude(&controllers.ReportController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
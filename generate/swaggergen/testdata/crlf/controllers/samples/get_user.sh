@@ -0,0 +1,2 @@
+curl -X GET \
+  https://api.example.com/v1/user/42
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Description.File docs/get_user.md
+// @Param id path string true "the id of the user"
+// @Success 200 {string} the user
+// @CodeSample curl samples/get_user.sh
+// @router /:id [get]
+func (u *UserController) Get() {
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	_ "fixtures/defaultresponse/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+// @DefaultResponse models.Response
+type UserController struct {
+	beego.Controller
+}
+
+// @Title DeleteUser
+// @Param id path string true "the id of the user"
+// @Success 200 deleted
+// @router /:id [delete]
+func (u *UserController) Delete() {
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User
+// @router /:id [get]
+func (u *UserController) Get() {
+}
@@ -0,0 +1,10 @@
+package models
+
+type User struct {
+	Name string `json:"name"`
+}
+
+type Response struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about the legacy users, use /user instead
+// @Deprecated
+type LegacyController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the users
+// @router / [get]
+func (l *LegacyController) GetAll() {
+}
+
+// @Title Get
+// @Deprecated
+// @Success 200 {string} the user
+// @router /:id [get]
+func (l *LegacyController) Get() {
+}
+
+// @Title Export
+// @Deprecated false
+// @Success 200 {string} the export of the users
+// @router /export [get]
+func (l *LegacyController) Export() {
+}
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) GetAll() {
+}
+
+// @Title Delete
+// @Deprecated true
+// @Success 200 {string} deleted
+// @router /:id [delete]
+func (u *UserController) Delete() {
+}
+
+// @Title Update
+// @Deprecated soon
+// @Success 200 {string} updated
+// @router /:id [put]
+func (u *UserController) Update() {
+}
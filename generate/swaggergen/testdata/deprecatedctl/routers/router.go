@@ -0,0 +1,21 @@
+// @APIVersion 1.0.0
+// @Title deprecatedctl
+package routers
+
+import (
+	"fixtures/deprecatedctl/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/legacy",
+			beego.NSInclude(&controllers.LegacyController{}),
+		),
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
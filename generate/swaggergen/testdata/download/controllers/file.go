@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about files
+type FileController struct {
+	beego.Controller
+}
+
+// @Title GetFile
+// @Param id path string true "the id"
+// @Success 200 {file} the file
+// @router /:id [get]
+func (c *FileController) Get() {
+}
+
+// @Title GetReport
+// @Success 200 {file} the report application/pdf
+// @router /report [get]
+func (c *FileController) Report() {
+}
@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title download
+package routers
+
+import (
+	"fixtures/download/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/file",
+			beego.NSInclude(&controllers.FileController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,5 @@
+package audit
+
+type Base struct {
+	CreatedBy string `json:"createdBy"`
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	_ "fixtures/embedded/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User
+// @router /:id [get]
+func (u *UserController) Get() {
+}
+
+// @Title GetOrder
+// @Param id path string true "the id of the order"
+// @Success 200 {object} models.Order
+// @router /order/:id [get]
+func (u *UserController) Order() {
+}
@@ -0,0 +1,17 @@
+package models
+
+import "fixtures/embedded/audit"
+
+type Base struct {
+	Id string `json:"id"`
+}
+
+type User struct {
+	Base
+	Name string `json:"name"`
+}
+
+type Order struct {
+	*audit.Base
+	Total int `json:"total"`
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	_ "fixtures/enums/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Success 200 {object} models.User
+// @router /:id [get]
+func (u *UserController) Get() {
+}
+
+// @Title ListUsers
+// @Param sort query models.Sort false "the order of the users"
+// @Success 200 {array} models.User
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title SearchUsers
+// @Param order query string false "the order" newest asc:desc
+// @Param sort query string false "the sort" asc asc:desc
+// @Param level query int false "the level" 2 1:2:3
+// @Param active query bool false "the activity" true true:false
+// @Param ratio query float64 false "the ratio" 0.5 0.5:1.5
+// @Success 200 {string} the users
+// @router /search [get]
+func (u *UserController) Search() {
+}
+
+// @Title GetTask
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.Task
+// @router /:id/task [get]
+func (u *UserController) Task() {
+}
@@ -0,0 +1,40 @@
+package models
+
+// Level is the level of a task
+type Level int
+
+const (
+	Low  Level = 1
+	High Level = 2
+)
+
+// Priority is the priority of a task
+type Priority uint8
+
+const (
+	Later Priority = iota + 10
+	Now
+)
+
+// Grade is the grade of a task
+type Grade int32
+
+const (
+	GradeA Grade = 'a'
+	GradeB Grade = 'b'
+)
+
+// Ratio is the part of a task which is done
+type Ratio float64
+
+const (
+	Half Ratio = 0.5
+	Full Ratio = 1
+)
+
+type Task struct {
+	Level    Level
+	Priority Priority
+	Grade    Grade
+	Ratio    Ratio
+}
@@ -0,0 +1,31 @@
+package models
+
+type Status int
+
+const (
+	Active Status = iota
+	Inactive
+	_
+	Banned
+)
+
+type Permission uint
+
+const (
+	Read Permission = 1 << iota
+	Write
+	Admin
+)
+
+type User struct {
+	Name        string       `json:"name"`
+	Status      Status       `json:"status"`
+	Permissions []Permission `json:"permissions"`
+}
+
+type Sort string
+
+const (
+	Ascending  Sort = "asc"
+	Descending Sort = "desc"
+)
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Administration of the users
+type AdminController struct {
+	beego.Controller
+}
+
+// @Title ListAccounts
+// @Success 200 {string} the accounts
+// @router /accounts [get]
+func (a *AdminController) List() {
+}
+
+// @Title Purge
+// @Security basic_auth
+// @Success 200 {string} purged
+// @router /accounts [delete]
+func (a *AdminController) Purge() {
+}
@@ -0,0 +1,23 @@
+// @APIVersion 1.0.0
+// @Title filterauth
+// @SecurityDefinition api_key apiKey X-API-Key header
+// @SecurityDefinition basic_auth basic
+package routers
+
+import (
+	"fixtures/filterauth/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+		beego.NSNamespace("/admin",
+			beego.NSInclude(&controllers.AdminController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
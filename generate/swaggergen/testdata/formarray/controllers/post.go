@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about posts
+type PostController struct {
+	beego.Controller
+}
+
+// @Title CreatePost
+// @Param title formData string true "the title"
+// @Param tags formData []string false "tags"
+// @Success 201 {string} created
+// @router / [post]
+func (p *PostController) Post() {
+}
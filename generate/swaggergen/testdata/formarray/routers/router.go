@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title form arrays
+package routers
+
+import (
+	"fixtures/formarray/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/post",
+			beego.NSInclude(&controllers.PostController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
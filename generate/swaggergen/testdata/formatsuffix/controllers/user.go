@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Success 200 {string} the user
+// @router /:id.:ext [get]
+func (u *UserController) Get(id int, ext string) {
+}
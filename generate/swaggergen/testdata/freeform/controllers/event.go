@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about events
+type EventController struct {
+	beego.Controller
+}
+
+// @Title CreateEvent
+// @Param data body object true "arbitrary json"
+// @Param meta query object false "the metadata"
+// @Success 200 {string} the event
+// @router / [post]
+func (c *EventController) Post() {
+}
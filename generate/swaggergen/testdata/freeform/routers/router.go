@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title freeform
+package routers
+
+import (
+	"fixtures/freeform/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/event",
+			beego.NSInclude(&controllers.EventController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	_ "fixtures/generics/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Success 200 {object} models.Response[[]models.User]
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title IndexUsers
+// @Success 200 {object} models.Response[map[string]models.User]
+// @router /index [get]
+func (u *UserController) Index() {
+}
+
+// @Title PageUsers
+// @Success 200 {object} models.Page[models.User]
+// @router /page [get]
+func (u *UserController) Page() {
+}
+
+// @Title GetTeam
+// @Success 200 {object} models.Team
+// @router /team [get]
+func (u *UserController) Team() {
+}
+
+// @Title GetMembership
+// @Success 200 {object} models.Pair[models.User,models.Group]
+// @router /membership [get]
+func (u *UserController) Membership() {
+}
@@ -0,0 +1,29 @@
+package models
+
+type User struct {
+	Id   int64
+	Name string
+}
+
+type Response[T any] struct {
+	Code int
+	Data T
+}
+
+type Page[T any] struct {
+	Items []T
+	Total int
+}
+
+type Pair[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+type Group struct {
+	Name string
+}
+
+type Team struct {
+	Members Page[User]
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations behind proxies
+type ProxyController struct {
+	beego.Controller
+}
+
+// @Title Forward
+// @Param X-Forwarded-For header []string false "the addresses of the clients and proxies"
+// @Param X-Hops header []string false "the hops" collectionFormat=pipes
+// @Param X-Tabs header []string false "the tabs" collectionFormat=tsv
+// @Success 200 {string} forwarded
+// @router / [get]
+func (p *ProxyController) Forward() {
+}
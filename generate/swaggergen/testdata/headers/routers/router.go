@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title headers
+package routers
+
+import (
+	"fixtures/headers/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/proxy",
+			beego.NSInclude(&controllers.ProxyController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
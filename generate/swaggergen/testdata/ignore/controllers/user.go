@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	"fixtures/ignore/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title Get
+// @Success 200 {object} models.User
+// @router / [get]
+func (u *UserController) Get() {
+	u.Data["json"] = models.User{}
+}
@@ -0,0 +1,12 @@
+package models
+
+type User struct {
+	Name     string `json:"name"`
+	Password string `json:"password" ignore:"true"`
+	Token    string `json:"token" swaggerignore:"true"`
+	Secret   Secret `json:"secret" ignore:"true"`
+}
+
+type Secret struct {
+	Key string
+}
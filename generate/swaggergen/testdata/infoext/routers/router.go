@@ -0,0 +1,22 @@
+// @APIVersion 1.0.0
+// @Title infoext
+// @TermsOfServiceUrl example.com/terms
+// @InfoExtension x-logo {"url": "https://example.com/logo.png"}
+// @InfoExtension x-audience public
+// @InfoExtension logo none
+package routers
+
+import (
+	"fixtures/infoext/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
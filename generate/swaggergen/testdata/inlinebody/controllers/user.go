@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title CreateUser
+// @Param body body {name:string required, age:int} true "the user"
+// @Success 201 {string} created
+// @router / [post]
+func (u *UserController) Post() {
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	_ "fixtures/jsondefault/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about searches
+type SearchController struct {
+	beego.Controller
+}
+
+// @Title Search
+// @Param body body models.Query true "the query"
+// @Success 200 {string} the results
+// @router / [post]
+func (s *SearchController) Post() {
+}
@@ -0,0 +1,13 @@
+package models
+
+type Paging struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type Query struct {
+	Paging  Paging            `json:"paging" default:"{\"limit\":10}"`
+	Sort    []string          `json:"sort" default:"[\"name\"]"`
+	Weights map[string]int    `json:"weights" default:"{oops"`
+	Filters map[string]string `json:"filters" default:"{\"status\":\"active\"}"`
+}
@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title json defaults
+package routers
+
+import (
+	"fixtures/jsondefault/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/search",
+			beego.NSInclude(&controllers.SearchController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
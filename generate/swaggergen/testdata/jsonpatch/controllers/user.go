@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	_ "fixtures/jsonpatch/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title PatchUser
+// @Param id path int true "the id"
+// @Accept json-patch
+// @Success 200 {object} models.User
+// @router /:id [patch]
+func (u *UserController) Patch() {
+}
+
+// @Title MergeUser
+// @Param id path int true "the id"
+// @Param body body models.User true "the fields to change"
+// @Accept merge-patch
+// @Success 200 {object} models.User
+// @router /:id/merge [patch]
+func (u *UserController) Merge() {
+}
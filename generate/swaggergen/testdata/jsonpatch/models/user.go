@@ -0,0 +1,6 @@
+package models
+
+type User struct {
+	Id   int64
+	Name string
+}
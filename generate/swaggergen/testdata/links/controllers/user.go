@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	_ "fixtures/links/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title Create
+// @Param body body models.User true "the user"
+// @Success 201 {object} models.User
+// @Link 201 GetUser operationId=UserController.Get params=id:$response.body#/id "the created user"
+// @router / [post]
+func (u *UserController) Post() {
+}
+
+// @Title Get
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User
+// @router /:id [get]
+func (u *UserController) Get() {
+}
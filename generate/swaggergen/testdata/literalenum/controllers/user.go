@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	_ "fixtures/literalenum/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Success 200 {object} models.User
+// @router / [get]
+func (u *UserController) Get() {
+}
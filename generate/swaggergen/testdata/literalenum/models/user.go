@@ -0,0 +1,29 @@
+package models
+
+// Status of a user
+// @Enum AllowedStatuses
+type Status string
+
+const StatusActive = "active"
+
+// AllowedStatuses are the statuses a user may be given
+var AllowedStatuses = map[string]bool{
+	StatusActive: true,
+	"archived":   true,
+}
+
+// Role of a user
+// @Enum models.Roles
+type Role int
+
+var Roles = []int{1, 2, 4}
+
+// Kind of a user
+// @Enum Kinds
+type Kind string
+
+type User struct {
+	Status Status
+	Role   Role
+	Kind   Kind
+}
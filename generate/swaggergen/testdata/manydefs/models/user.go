@@ -0,0 +1,20 @@
+package models
+
+import (
+	"fixtures/manydefs/shared"
+)
+
+type User struct {
+	Name    string
+	Address shared.Address
+	Profile Profile
+	Groups  []Group
+}
+
+type Profile struct {
+	Bio string
+}
+
+type Group struct {
+	Name string
+}
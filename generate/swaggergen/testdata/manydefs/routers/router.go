@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title manydefs
+package routers
+
+import (
+	"fixtures/manydefs/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,5 @@
+package shared
+
+type Address struct {
+	City string
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	_ "fixtures/maps/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about catalogs
+type CatalogController struct {
+	beego.Controller
+}
+
+// @Title GetCatalog
+// @Success 200 {object} models.Catalog
+// @router / [get]
+func (c *CatalogController) Get() {
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	_ "fixtures/maps/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about trees
+type TreeController struct {
+	beego.Controller
+}
+
+// @Title GetTree
+// @Success 200 {object} models.TreeNode
+// @router / [get]
+func (c *TreeController) Get() {
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	_ "fixtures/maps/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User
+// @router /:id [get]
+func (u *UserController) Get() {
+}
+
+// @Title GetStatuses
+// @Success 200 {object} map[string]models.Status
+// @router /statuses [get]
+func (u *UserController) Statuses() {
+}
+
+// @Title PutStatuses
+// @Param body body map[string]models.Status true "the statuses of the users"
+// @Success 200 {string} ok
+// @router /statuses [put]
+func (u *UserController) PutStatuses() {
+}
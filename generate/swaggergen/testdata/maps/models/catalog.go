@@ -0,0 +1,24 @@
+package models
+
+type Label struct {
+	Text string
+}
+
+// Code is a numeric key
+type Code int
+
+// Locale is a string key
+type Locale string
+
+type Catalog struct {
+	Names   map[string]string `json:"names" keypattern:"^[a-z]{2}$"`
+	Labels  map[string]Label  `json:"labels" keypattern:"^[a-z]{2}$"`
+	Count   int               `json:"count" keypattern:"^[a-z]+$"`
+	Stock   map[int]Label     `json:"stock"`
+	Codes   map[Code]string   `json:"codes"`
+	Locales map[Locale]Label  `json:"locales"`
+
+	Sections map[string]map[string]Label `json:"sections"`
+	Shelves  map[string][]Label          `json:"shelves"`
+	Pages    []map[string]Label          `json:"pages"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type TreeNode struct {
+	Name     string
+	Children map[string]*TreeNode
+	Groups   map[string][]TreeNode
+	Nested   map[string]map[string]*TreeNode
+}
@@ -0,0 +1,14 @@
+package models
+
+// Status is the status of a user
+type Status string
+
+const (
+	Active   Status = "active"
+	Archived Status = "archived"
+)
+
+type User struct {
+	Name     string
+	Statuses map[string]Status
+}
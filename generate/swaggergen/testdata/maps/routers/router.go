@@ -0,0 +1,24 @@
+// @APIVersion 1.0.0
+// @Title maps
+package routers
+
+import (
+	"fixtures/maps/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/tree",
+			beego.NSInclude(&controllers.TreeController{}),
+		),
+		beego.NSNamespace("/catalog",
+			beego.NSInclude(&controllers.CatalogController{}),
+		),
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about uploads
+type UploadController struct {
+	beego.Controller
+}
+
+// @Title Upload
+// @Param body body file true "raw upload"
+// @MaxBodySize 10485760
+// @Success 201 {string} the id of the upload
+// @router / [put]
+func (u *UploadController) Put() {
+}
+
+// @Title UploadForm
+// @Param name formData string true "the name of the upload"
+// @Param content formData file true "the content of the upload"
+// @MaxBodySize 10485760
+// @Success 201 {string} the id of the upload
+// @router /form [post]
+func (u *UploadController) Post() {
+}
+
+// @Title GetUpload
+// @Param id path string true "the id of the upload"
+// @MaxBodySize 1024
+// @Success 200 {file} the upload
+// @router /:id [get]
+func (u *UploadController) Get() {
+}
+
+// @Title DeleteUpload
+// @Param id path string true "the id of the upload"
+// @MaxBodySize 10MB
+// @Success 204 {string} deleted
+// @router /:id [delete]
+func (u *UploadController) Delete() {
+}
@@ -0,0 +1,15 @@
+package controllers
+
+import "github.com/astaxie/beego"
+
+// Operations about files
+type FileController struct {
+	beego.Controller
+}
+
+// @Title GetFile
+// @Param name path string true "name of the file"
+// @Success 200 {file} the file
+// @router /:name [get]
+func (f *FileController) Get() {
+}
@@ -0,0 +1,21 @@
+// @APIVersion 1.0.0
+// @Title mounts
+package routers
+
+import (
+	"fixtures/mounts/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	api := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	files := beego.NewNamespace("/files",
+		beego.NSInclude(&controllers.FileController{}),
+	)
+	beego.AddNamespace(api, files)
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	_ "fixtures/multipart/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Uploads of documents
+type UploadController struct {
+	beego.Controller
+}
+
+// @Title Upload
+// @Part metadata application/json models.UploadMeta "the metadata of the file"
+// @Part file application/octet-stream
+// @Success 201 {string} the id of the document
+// @router / [post]
+func (u *UploadController) Post() {
+}
@@ -0,0 +1,6 @@
+package models
+
+type UploadMeta struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {string} the user
+// @router /:id [get]
+// @router /by-id/:id [get,head]
+func (u *UserController) Get() {
+}
+
+// @Title GetMe
+// @Success 200 {string} the current user
+// @router /me [get]
+// @router /self [get]
+func (u *UserController) Me(lang string) {
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	_ "fixtures/namedlist/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Success 200 {object} models.UserList
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title ListRefs
+// @Success 200 {object} models.UserRefs
+// @router /refs [get]
+func (u *UserController) Refs() {
+}
+
+// @Title ListNames
+// @Success 200 {object} models.Names
+// @router /names [get]
+func (u *UserController) Names() {
+}
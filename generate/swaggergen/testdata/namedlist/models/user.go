@@ -0,0 +1,12 @@
+package models
+
+type User struct {
+	Id   int64
+	Name string
+}
+
+type UserList []User
+
+type UserRefs []*User
+
+type Names []string
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	_ "fixtures/nested/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about companies
+type CompanyController struct {
+	beego.Controller
+}
+
+// @Title GetCompany
+// @Param id path string true "the id of the company"
+// @Success 200 {object} models.Company
+// @router /:id [get]
+func (c *CompanyController) Get() {
+}
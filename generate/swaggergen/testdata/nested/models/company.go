@@ -0,0 +1,25 @@
+package models
+
+type Company struct {
+	Name        string       `json:"name"`
+	Departments []Department `json:"departments"`
+}
+
+type Department struct {
+	Name    string            `json:"name"`
+	Manager *Employee         `json:"manager"`
+	Staff   map[string]Person `json:"staff"`
+}
+
+type Employee struct {
+	Person
+	Address Address `json:"address"`
+}
+
+type Person struct {
+	Name string `json:"name"`
+}
+
+type Address struct {
+	City string `json:"city"`
+}
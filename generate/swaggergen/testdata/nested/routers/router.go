@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title nested models
+package routers
+
+import (
+	"fixtures/nested/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/company",
+			beego.NSInclude(&controllers.CompanyController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
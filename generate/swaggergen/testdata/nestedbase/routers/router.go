@@ -0,0 +1,26 @@
+// @APIVersion 1.0.0
+// @Title nested base
+// @Base /api/v1
+package routers
+
+import (
+	"fixtures/mounts/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/api",
+		beego.NSNamespace("/v1",
+			beego.NSNamespace("/user",
+				beego.NSInclude(&controllers.UserController{}),
+			),
+		),
+		beego.NSNamespace("/v2",
+			beego.NSNamespace("/files",
+				beego.NSInclude(&controllers.FileController{}),
+			),
+		),
+	)
+	beego.AddNamespace(ns)
+}
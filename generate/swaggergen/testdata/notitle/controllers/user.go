@@ -0,0 +1,15 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) List() {
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	_ "fixtures/nullable/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User
+// @router /:id [get]
+func (u *UserController) Get() {
+}
@@ -0,0 +1,17 @@
+package models
+
+// Status of a user
+type Status int
+
+const (
+	Active Status = iota
+	Inactive
+)
+
+type User struct {
+	Emails  *[]string          `json:"emails"`
+	Labels  *map[string]string `json:"labels"`
+	Aliases []string           `json:"aliases"`
+	Status  *Status            `json:"status"`
+	State   Status             `json:"state"`
+}
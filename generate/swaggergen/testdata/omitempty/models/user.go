@@ -0,0 +1,10 @@
+package models
+
+type User struct {
+	Id       int64  `json:"id"`
+	Name     string `json:"name" required:"true"`
+	Nickname string `json:"nickname,omitempty"`
+	Email    string `json:"email,omitempty" required:"true"`
+	Age      int
+	Address  string `json:",omitempty"`
+}
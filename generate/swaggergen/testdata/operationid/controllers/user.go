@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @OperationId listUsers
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) GetAll() {
+}
+
+// @OperationId getUser
+// @Title Get
+// @Success 200 {string} the user
+// @router /:id [get]
+func (u *UserController) Get() {
+}
+
+// @Title Delete
+// @Success 200 {string} deleted
+// @router /:id [delete]
+func (u *UserController) Delete() {
+}
+
+// @Title Remove
+// @OperationId getUser
+// @Success 200 {string} removed
+// @router /:id/remove [post]
+func (u *UserController) Remove() {
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import "github.com/astaxie/beego"
+
+// Static assets
+type AssetController struct {
+	beego.Controller
+}
+
+// @Title GetAsset
+// @Param name path string true "name of the asset"
+// @Server https://cdn.example.com/v1 "content delivery network"
+// @Success 200 {file} the asset
+// @router /:name [get]
+func (a *AssetController) Get() {
+}
+
+// @Title ListAssets
+// @Success 200 {string} the assets
+// @router / [get]
+func (a *AssetController) List() {
+}
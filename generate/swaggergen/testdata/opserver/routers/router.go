@@ -0,0 +1,20 @@
+// @APIVersion 1.0.0
+// @Title operation server
+// @Host api.example.com
+// @Schemes https
+package routers
+
+import (
+	"fixtures/opserver/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/asset",
+			beego.NSInclude(&controllers.AssetController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
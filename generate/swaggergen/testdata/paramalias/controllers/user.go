@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param userId=>uid path string true "the id of the user"
+// @Success 200 {string} the name of the user
+// @router /:userId [get]
+func (u *UserController) Get(id string) {
+}
+
+// @Title DeleteUser
+// @Param userId=>id path string true "the id of the user"
+// @Success 204 {string} deleted
+// @router /:userId [delete]
+func (u *UserController) Delete(id string) {
+}
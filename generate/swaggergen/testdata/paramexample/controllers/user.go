@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	_ "fixtures/paramexample/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Param limit query int false "the limit" 10 example=50
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title CreateUser
+// @Param body body models.User true "the user" example={"Name":"alice"}
+// @Success 200 {string} the user
+// @router / [post]
+func (u *UserController) Post() {
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	_ "fixtures/queryflat/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title SearchUsers
+// @Param filter query models.Filter true "the filter"
+// @Success 200 {string} the users
+// @router /search [get]
+func (u *UserController) Search() {
+}
@@ -0,0 +1,12 @@
+package models
+
+type Paging struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+type Filter struct {
+	Paging
+	Query string   `json:"q" required:"true"`
+	Tags  []string `json:"tags"`
+}
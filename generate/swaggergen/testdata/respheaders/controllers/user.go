@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Header 200 X-Rate-Limit int "the requests left"
+// @Header 200 X-Request-Id string "the id of the listing"
+// @Success 200 {string} the users
+// @Failure 500 server error
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title DeleteUser
+// @Success 204 deleted
+// @router / [delete]
+func (u *UserController) Delete() {
+}
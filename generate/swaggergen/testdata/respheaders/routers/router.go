@@ -0,0 +1,19 @@
+// @APIVersion 1.0.0
+// @Title respheaders
+// @Header X-Request-Id string "the id of the request"
+package routers
+
+import (
+	"fixtures/respheaders/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	_ "fixtures/respschema/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about accounts
+type AccountController struct {
+	beego.Controller
+}
+
+// @Title GetOwner
+// @Param id path string true "the id of the account"
+// @Success 200 {object} models.User "the owner" title=Owner description="The owner of the account"
+// @router /:id/owner [get]
+func (a *AccountController) Owner() {
+}
+
+// @Title GetMember
+// @Param id path string true "the id of the member"
+// @Success 200 {object} models.User the member
+// @router /members/:id [get]
+func (a *AccountController) Member() {
+}
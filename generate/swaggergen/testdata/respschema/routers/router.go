@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title respschema
+package routers
+
+import (
+	"fixtures/respschema/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/account",
+			beego.NSInclude(&controllers.AccountController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,22 @@
+// @APIVersion 1.0.0
+// @Title rootannotations
+// @LicenseUrl https://www.apache.org/licenses/LICENSE-2.0.html
+// @License Apache 2.0
+// @Security api_key
+// @SecurityDefinition api_key apiKey X-API-Key header "the key of the client"
+package routers
+
+import (
+	"fixtures/rootannotations/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,16 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Health of the service
+type HealthController struct {
+	beego.Controller
+}
+
+// @Title Ping
+// @Success 200 {string} pong
+// @router /ping [get]
+func (c *HealthController) Ping() {
+}
@@ -0,0 +1,19 @@
+// @APIVersion 1.0.0
+// @Title roottag
+package routers
+
+import (
+	"fixtures/roottag/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSInclude(&controllers.HealthController{}),
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
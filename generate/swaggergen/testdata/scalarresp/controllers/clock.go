@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about the clock
+type ClockController struct {
+	beego.Controller
+}
+
+// @Title Now
+// @Success 200 {string:date-time} "timestamp"
+// @router /now [get]
+func (c *ClockController) Now() {
+}
+
+// @Title Ticks
+// @Success 200 {int64} the ticks since the start example=42
+// @router /ticks [get]
+func (c *ClockController) Ticks() {
+}
+
+// @Title Zone
+// @Success 200 {integer:int32} the offset of the zone example=east
+// @router /zone [get]
+func (c *ClockController) Zone() {
+}
+
+// @Title Started
+// @Success 200 {string:date-time} the start example=2026-01-02T03:04:05Z
+// @router /started [get]
+func (c *ClockController) Started() {
+}
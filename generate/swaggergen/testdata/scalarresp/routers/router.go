@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title scalarresp
+package routers
+
+import (
+	"fixtures/scalarresp/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/clock",
+			beego.NSInclude(&controllers.ClockController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,19 @@
+// @APIVersion 1.0.0
+// @Title scopes
+// @SecurityDefinition petstore_auth oauth2 https://example.com/oauth/authorize implicit write:pets "modify pets" read:pets "read pets" admin "administer the store"
+package routers
+
+import (
+	"fixtures/scopes/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
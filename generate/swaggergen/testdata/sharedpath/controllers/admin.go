@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Administration of the users
+type AdminController struct {
+	beego.Controller
+}
+
+// @Title DeleteUser
+// @Param id path string true "the id"
+// @Success 204 deleted
+// @router /:id [delete]
+func (a *AdminController) Delete() {
+}
@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id"
+// @Success 200 {string} the user
+// @router /:id [get]
+func (u *UserController) Get() {
+}
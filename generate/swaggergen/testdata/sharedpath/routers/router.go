@@ -0,0 +1,21 @@
+// @APIVersion 1.0.0
+// @Title sharedpath
+package routers
+
+import (
+	"fixtures/sharedpath/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/users",
+			beego.NSInclude(
+				&controllers.UserController{},
+				&controllers.AdminController{},
+			),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,6 @@
+package models
+
+const (
+	Red  Color = "red"
+	Blue Color = "blue"
+)
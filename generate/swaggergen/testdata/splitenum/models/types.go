@@ -0,0 +1,11 @@
+package models
+
+import "fixtures/splitenum/paint"
+
+// Color of a user
+type Color string
+
+type User struct {
+	Favorite Color
+	Wall     paint.Color
+}
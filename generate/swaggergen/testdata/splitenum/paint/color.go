@@ -0,0 +1,9 @@
+package paint
+
+// Color of a paint
+type Color int
+
+const (
+	Matte Color = iota + 1
+	Gloss
+)
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	_ "fixtures/strict/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Success 200 {object} models.User
+// @router / [get]
+func (u *UserController) Get() {
+}
+
+// @Title DeleteUser
+// @Success 200 {object} models.Ack
+// @router / [delete]
+func (u *UserController) Delete() {
+}
+
+// @Title ListUsers
+// @Success 200 {object} models.Missing
+// @router /list [get]
+func (u *UserController) List() {
+}
@@ -0,0 +1,11 @@
+package models
+
+type User struct {
+	Id   int64
+	Name string
+}
+
+// Ack is returned by the operations without any result
+// @EmptyModel
+type Ack struct {
+}
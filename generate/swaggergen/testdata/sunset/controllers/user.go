@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Deprecated true
+// @Sunset 2027-01-01
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title GetUser
+// @Deprecated true
+// @Sunset soon
+// @Param id path string true "the id of the user"
+// @Success 200 {string} the user
+// @router /:id [get]
+func (u *UserController) Get() {
+}
@@ -0,0 +1,19 @@
+package controllers
+
+import (
+	_ "fixtures/swaggertype/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about events
+type EventController struct {
+	beego.Controller
+}
+
+// @Title GetEvent
+// @Param id path string true "the id of the event"
+// @Success 200 {object} models.Event
+// @router /:id [get]
+func (e *EventController) Get() {
+}
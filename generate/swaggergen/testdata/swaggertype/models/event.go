@@ -0,0 +1,11 @@
+package models
+
+type User struct {
+	Name string `json:"name"`
+}
+
+type Event struct {
+	Payload interface{} `json:"payload" swaggertype:"models.User"`
+	Tags    interface{} `json:"tags" swaggertype:"[]string"`
+	Extra   interface{} `json:"extra"`
+}
@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title	ListUsers
+// @Param	status	query	string	false	"the	status of the users"	active	active:archived
+// @Param 	 limit 	 query int	 false  "the limit"
+// @Success	200	{string}	the users
+// @router	/	[get]
+func (u *UserController) List() {
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) GetAll() {
+}
+
+// Operations about orders
+type OrderController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the orders
+// @router / [get]
+func (o *OrderController) GetAll() {
+}
+
+// Operations about the administration
+type AdminController struct {
+	beego.Controller
+}
+
+// @Title GetAll
+// @Success 200 {string} the settings
+// @router / [get]
+func (a *AdminController) GetAll() {
+}
@@ -0,0 +1,27 @@
+// @APIVersion 1.0.0
+// @Title taggroups
+// @TagGroup Shop user,order
+// @TagGroup "Back office" admin,report
+// @TagGroup Shop cart
+package routers
+
+import (
+	"fixtures/taggroups/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+		beego.NSNamespace("/order",
+			beego.NSInclude(&controllers.OrderController{}),
+		),
+		beego.NSNamespace("/admin",
+			beego.NSInclude(&controllers.AdminController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+// @Tags accounts, admin
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title DeleteUser
+// @Param id path string true "the id of the user"
+// @Success 204 {string} deleted
+// @router /:id [delete]
+func (u *UserController) Delete() {
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	_ "fixtures/testpkg/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {object} models.User
+// @router /:id [get]
+func (u *UserController) Get() {
+}
+
+// @Title GetSample
+// @Success 200 {object} models.Sample
+// @router /sample [get]
+func (u *UserController) Sample() {
+}
@@ -0,0 +1,9 @@
+package models_test
+
+type User struct {
+	Example string `json:"example"`
+}
+
+type Sample struct {
+	Id string `json:"id"`
+}
@@ -0,0 +1,14 @@
+package controllers
+
+import "github.com/astaxie/beego"
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title GetUserList
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) GetAll() {
+}
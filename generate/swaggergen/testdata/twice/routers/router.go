@@ -0,0 +1,21 @@
+// @APIVersion 1.0.0
+// @Title twice
+package routers
+
+import (
+	"fixtures/twice/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+		beego.NSNamespace("/admin",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
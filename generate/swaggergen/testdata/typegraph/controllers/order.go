@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	_ "fixtures/typegraph/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about orders
+type OrderController struct {
+	beego.Controller
+}
+
+// @Title GetCustomer
+// @Param body body models.Customer true "the customer"
+// @Success 200 {object} models.Customer
+// @router /customer [post]
+func (o *OrderController) PostCustomer() {
+}
+
+// @Title GetOrder
+// @Param body body models.Order true "the order"
+// @Success 200 {object} models.Order
+// @router /order [post]
+func (o *OrderController) PostOrder() {
+}
+
+// @Title GetLine
+// @Param body body models.Line true "the line"
+// @Success 200 {object} models.Line
+// @router /line [post]
+func (o *OrderController) PostLine() {
+}
+
+// @Title GetProduct
+// @Param body body models.Product true "the product"
+// @Success 200 {object} models.Product
+// @router /product [post]
+func (o *OrderController) PostProduct() {
+}
+
+// @Title GetInvoice
+// @Param body body models.Invoice true "the invoice"
+// @Success 200 {object} models.Invoice
+// @router /invoice [post]
+func (o *OrderController) PostInvoice() {
+}
+
+// @Title GetPayment
+// @Param body body models.Payment true "the payment"
+// @Success 200 {object} models.Payment
+// @router /payment [post]
+func (o *OrderController) PostPayment() {
+}
+
+// @Title GetRefund
+// @Param body body models.Refund true "the refund"
+// @Success 200 {object} models.Refund
+// @router /refund [post]
+func (o *OrderController) PostRefund() {
+}
+
+// @Title GetShipment
+// @Param body body models.Shipment true "the shipment"
+// @Success 200 {object} models.Shipment
+// @router /shipment [post]
+func (o *OrderController) PostShipment() {
+}
+
+// @Title GetParcel
+// @Param body body models.Parcel true "the parcel"
+// @Success 200 {object} models.Parcel
+// @router /parcel [post]
+func (o *OrderController) PostParcel() {
+}
+
+// @Title GetCarrier
+// @Param body body models.Carrier true "the carrier"
+// @Success 200 {object} models.Carrier
+// @router /carrier [post]
+func (o *OrderController) PostCarrier() {
+}
@@ -0,0 +1,93 @@
+package models
+
+import "fixtures/typegraph/types"
+
+type Customer struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Order         `json:"next"`
+}
+
+type Order struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Line          `json:"next"`
+}
+
+type Line struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Product       `json:"next"`
+}
+
+type Product struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Invoice       `json:"next"`
+}
+
+type Invoice struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Payment       `json:"next"`
+}
+
+type Payment struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Refund        `json:"next"`
+}
+
+type Refund struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Shipment      `json:"next"`
+}
+
+type Shipment struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Parcel        `json:"next"`
+}
+
+type Parcel struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Carrier       `json:"next"`
+}
+
+type Carrier struct {
+	Id       string         `json:"id"`
+	Total    types.Money    `json:"total"`
+	Billing  types.Address  `json:"billing"`
+	Shipping *types.Address `json:"shipping"`
+	Prices   []types.Money  `json:"prices"`
+	Next     *Customer      `json:"next"`
+}
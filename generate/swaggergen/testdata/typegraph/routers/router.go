@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title type graph
+package routers
+
+import (
+	"fixtures/typegraph/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/order",
+			beego.NSInclude(&controllers.OrderController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	_ "fixtures/types/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about events
+type EventController struct {
+	beego.Controller
+}
+
+// @Title GetEvent
+// @Param id path string true "the id of the event"
+// @Param timeout query time.Duration false "the time to wait for the event"
+// @Success 200 {object} models.Event
+// @router /:id [get]
+func (e *EventController) Get() {
+}
@@ -0,0 +1,7 @@
+// Package decimal stands for github.com/shopspring/decimal
+package decimal
+
+type Decimal struct {
+	value []byte
+	exp   int32
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"fixtures/types/decimal"
+	"fixtures/types/uuid"
+)
+
+type Event struct {
+	ID       uuid.UUID
+	Related  []uuid.UUID
+	Timeout  time.Duration
+	Month    time.Month
+	Weekday  time.Weekday
+	Retries  []time.Duration
+	Deadline *time.Duration
+	Price    decimal.Decimal
+	Note     sql.NullString
+}
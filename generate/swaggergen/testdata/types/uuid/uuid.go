@@ -0,0 +1,4 @@
+// Package uuid stands for github.com/google/uuid
+package uuid
+
+type UUID [16]byte
@@ -0,0 +1,7 @@
+package models
+
+type User struct {
+	Name     string `json:"name"`
+	password string `json:"password"`
+	token    string
+}
@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/beego/beego/v2/server/web"
+)
+
+// Operations about users
+type UserController struct {
+	web.Controller
+}
+
+// @Title GetUser
+// @Param id path string true "the id of the user"
+// @Success 200 {string} the name of the user
+// @router /:id [get]
+func (u *UserController) Get() {
+}
@@ -0,0 +1,18 @@
+// @APIVersion 1.0.0
+// @Title beego v2
+package routers
+
+import (
+	"fixtures/v2/controllers"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+func init() {
+	ns := web.NewNamespace("/v1",
+		web.NSNamespace("/user",
+			web.NSInclude(&controllers.UserController{}),
+		),
+	)
+	web.AddNamespace(ns)
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	_ "fixtures/xmlarray/models"
+
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Success 200 {array} models.User
+// @Accept json,xml
+// @router / [get]
+func (u *UserController) List() {
+}
+
+// @Title ListStatuses
+// @Success 200 {array} string
+// @Accept xml
+// @router /statuses [get]
+func (u *UserController) Statuses() {
+}
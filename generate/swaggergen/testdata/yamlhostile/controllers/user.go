@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"github.com/astaxie/beego"
+)
+
+// Operations about users
+type UserController struct {
+	beego.Controller
+}
+
+// @Title ListUsers
+// @Description.File docs/list.md
+// @Success 200 {string} the users
+// @router / [get]
+func (u *UserController) List() {
+}
@@ -0,0 +1,19 @@
+// @APIVersion 1.0.0
+// @Title yamlhostile
+// @Description @users: - the	users # of the *store* | > {x: [y]} 'quoted' "double"
+package routers
+
+import (
+	"fixtures/yamlhostile/controllers"
+
+	"github.com/astaxie/beego"
+)
+
+func init() {
+	ns := beego.NewNamespace("/v1",
+		beego.NSNamespace("/user",
+			beego.NSInclude(&controllers.UserController{}),
+		),
+	)
+	beego.AddNamespace(ns)
+}
@@ -68,6 +68,18 @@ func IsInGOPATH(thePath string) bool {
 	return false
 }
 
+// IsInModule checks whether the path is inside of a Go module, i.e. a go.mod file is found in it or any of its parents
+func IsInModule(thePath string) bool {
+	for dir := thePath; ; dir = filepath.Dir(dir) {
+		if IsExist(filepath.Join(dir, "go.mod")) {
+			return true
+		}
+		if filepath.Dir(dir) == dir {
+			return false
+		}
+	}
+}
+
 // IsBeegoProject checks whether the current path is a Beego application or not
 func IsBeegoProject(thePath string) bool {
 	mainFiles := []string{}